@@ -0,0 +1,54 @@
+package core
+
+import (
+	"database/sql/driver"
+)
+
+// Value Implement driver.Valuer and sql.Scanner interfaces on StringValues, stored as a JSON column
+func (x *StringValues) Value() (driver.Value, error) {
+	if x == nil {
+		return nil, nil
+	}
+	return sqlJSONValue(x)
+}
+
+func (x *StringValues) Scan(src interface{}) error {
+	x.Reset()
+	return scanSQLJSON(src, x)
+}
+
+func (x *StringValues) GormDataType() string {
+	return "json"
+}
+
+func (x *StringMap) Value() (driver.Value, error) {
+	if x == nil {
+		return nil, nil
+	}
+	return sqlJSONValue(x)
+}
+
+func (x *StringMap) Scan(src interface{}) error {
+	x.Reset()
+	return scanSQLJSON(src, x)
+}
+
+func (x *StringMap) GormDataType() string {
+	return "json"
+}
+
+func (x *StringsMap) Value() (driver.Value, error) {
+	if x == nil {
+		return nil, nil
+	}
+	return sqlJSONValue(x)
+}
+
+func (x *StringsMap) Scan(src interface{}) error {
+	x.Reset()
+	return scanSQLJSON(src, x)
+}
+
+func (x *StringsMap) GormDataType() string {
+	return "json"
+}
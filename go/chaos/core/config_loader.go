@@ -0,0 +1,369 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chaos-io/core/go/chaos/core/strcase"
+	jsoniter "github.com/json-iterator/go"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigSource tells which layer of a ConfigLoader provided a field.
+type ConfigSource int
+
+const (
+	ConfigSourceUnset ConfigSource = iota
+	ConfigSourceDefault
+	ConfigSourceFile
+	ConfigSourceEnv
+	ConfigSourceOverride
+)
+
+func (s ConfigSource) String() string {
+	switch s {
+	case ConfigSourceDefault:
+		return "default"
+	case ConfigSourceFile:
+		return "file"
+	case ConfigSourceEnv:
+		return "env"
+	case ConfigSourceOverride:
+		return "override"
+	default:
+		return "unset"
+	}
+}
+
+// ConfigLoader fills a struct from layered sources, each one overriding the
+// previous: `default:"..."` struct tags, JSON/YAML files, environment
+// variables and explicit overrides. The layers are merged into an Object
+// which is finally decoded into the target with Object.To.
+//
+// Field paths use the json names of the struct fields joined by dots, e.g.
+// "file.maxSize". The environment variable of a field is the prefix followed
+// by the screaming snake case of each path segment, e.g. LOGS_FILE_MAX_SIZE.
+type ConfigLoader struct {
+	files     []string
+	section   string
+	envPrefix string
+	overrides []configOverride
+
+	object  *Object
+	sources map[string]ConfigSource
+}
+
+type configOverride struct {
+	path string
+	val  any
+}
+
+type configField struct {
+	path       []string
+	typ        reflect.Type
+	defaultVal string
+	hasDefault bool
+}
+
+func NewConfigLoader() *ConfigLoader {
+	return &ConfigLoader{}
+}
+
+// WithFile appends a JSON or YAML file, chosen by its extension. Missing
+// files are skipped.
+func (l *ConfigLoader) WithFile(files ...string) *ConfigLoader {
+	l.files = append(l.files, files...)
+	return l
+}
+
+// WithSection reads the config from the given top-level key of the files,
+// e.g. "logs" for a file shaped like `logs: {level: info}`.
+func (l *ConfigLoader) WithSection(section string) *ConfigLoader {
+	l.section = section
+	return l
+}
+
+func (l *ConfigLoader) WithEnvPrefix(prefix string) *ConfigLoader {
+	l.envPrefix = prefix
+	return l
+}
+
+// WithOverride sets a field by its dotted path, taking precedence over all
+// other sources. Overrides are applied in the order they are added, so a
+// later one wins over an earlier one of the same or an enclosing path.
+func (l *ConfigLoader) WithOverride(path string, val any) *ConfigLoader {
+	l.overrides = append(l.overrides, configOverride{path: path, val: val})
+	return l
+}
+
+// Load fills val, which must be a pointer to a struct.
+func (l *ConfigLoader) Load(val any) error {
+	rv := reflect.ValueOf(val)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("ConfigLoader.Load: expected a pointer to struct, got %T", val)
+	}
+
+	fields := configFields(rv.Elem().Type(), nil)
+	fieldsByPath := make(map[string]*configField, len(fields))
+	for _, field := range fields {
+		fieldsByPath[strings.Join(field.path, ".")] = field
+	}
+	l.object = NewObject()
+	l.sources = make(map[string]ConfigSource)
+
+	for _, field := range fields {
+		if !field.hasDefault {
+			continue
+		}
+		v, err := parseConfigValue(field.typ, field.defaultVal)
+		if err != nil {
+			return fmt.Errorf("invalid default of %s: %w", strings.Join(field.path, "."), err)
+		}
+		l.set(field.path, v, ConfigSourceDefault)
+	}
+
+	for _, file := range l.files {
+		obj, err := readConfigFile(file)
+		if err != nil {
+			return err
+		}
+		if len(l.section) > 0 {
+			obj = obj.GetObject(l.section)
+		}
+		if err = l.merge(nil, obj, fieldsByPath, ConfigSourceFile); err != nil {
+			return fmt.Errorf("invalid config file %s: %w", file, err)
+		}
+	}
+
+	if len(l.envPrefix) > 0 {
+		for _, field := range fields {
+			name := configEnvName(l.envPrefix, field.path)
+			str, ok := os.LookupEnv(name)
+			if !ok {
+				continue
+			}
+			v, err := parseConfigValue(field.typ, str)
+			if err != nil {
+				return fmt.Errorf("invalid environment variable %s: %w", name, err)
+			}
+			l.set(field.path, v, ConfigSourceEnv)
+		}
+	}
+
+	for _, o := range l.overrides {
+		v, err := NewValue(o.val)
+		if err != nil {
+			return fmt.Errorf("invalid override of %s: %w", o.path, err)
+		}
+		l.set(strings.Split(o.path, "."), v, ConfigSourceOverride)
+	}
+
+	return l.object.To(val)
+}
+
+// Object returns an immutable snapshot of the merged Object of the last
+// Load, which may be shared between goroutines, see FrozenObject.
+func (l *ConfigLoader) Object() *FrozenObject {
+	if l.object == nil {
+		return nil
+	}
+	// each Load merges into a new Object, so the last one is not mutated anymore
+	return &FrozenObject{obj: l.object}
+}
+
+// Source returns the layer which provided the field at path in the last Load.
+func (l *ConfigLoader) Source(path string) ConfigSource {
+	return l.sources[path]
+}
+
+// Sources returns the layer of every field set in the last Load, keyed by
+// dotted path.
+func (l *ConfigLoader) Sources() map[string]ConfigSource {
+	return l.sources
+}
+
+func (l *ConfigLoader) set(path []string, val *Value, source ConfigSource) {
+	obj := l.object
+	for _, key := range path[:len(path)-1] {
+		next := obj.GetObject(key)
+		if next == nil {
+			next = NewObject()
+			obj.SetObject(key, next)
+		}
+		obj = next
+	}
+	obj.SetValue(path[len(path)-1], val)
+	l.sources[strings.Join(path, ".")] = source
+}
+
+// merge sets the values of obj, parsing the strings of non-string fields
+// like parseConfigValue does for the defaults and the environment, so that
+// a file may give a time.Duration as "5s".
+func (l *ConfigLoader) merge(path []string, obj *Object, fields map[string]*configField, source ConfigSource) error {
+	for k, v := range obj.GetVals() {
+		p := append(append([]string{}, path...), k)
+		if sub := v.GetObject(); sub != nil {
+			if err := l.merge(p, sub, fields, source); err != nil {
+				return err
+			}
+			continue
+		}
+		name := strings.Join(p, ".")
+		if field, ok := fields[name]; ok && v.GetKind() == ValueKind_VALUE_KIND_STRING && field.typ.Kind() != reflect.String {
+			parsed, err := parseConfigValue(field.typ, v.GetString())
+			if err != nil {
+				return fmt.Errorf("invalid value of %s: %w", name, err)
+			}
+			v = parsed
+		}
+		l.set(p, v, source)
+	}
+	return nil
+}
+
+func configFields(typ reflect.Type, path []string) []*configField {
+	var fields []*configField
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		name, ok := jsonFieldName(f)
+		if !ok {
+			continue
+		}
+
+		p := append(append([]string{}, path...), name)
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr && ft.Elem().Kind() == reflect.Struct {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != reflect.TypeOf(time.Time{}) {
+			fields = append(fields, configFields(ft, p)...)
+			continue
+		}
+
+		defaultVal, hasDefault := f.Tag.Lookup("default")
+		fields = append(fields, &configField{path: p, typ: ft, defaultVal: defaultVal, hasDefault: hasDefault})
+	}
+	return fields
+}
+
+func configEnvName(prefix string, path []string) string {
+	names := make([]string, 0, len(path)+1)
+	names = append(names, strings.TrimSuffix(prefix, "_"))
+	for _, p := range path {
+		names = append(names, strcase.ToScreamingSnake(p))
+	}
+	return strings.Join(names, "_")
+}
+
+// parseConfigValue parses a tag or environment string by the kind of the
+// target field. Slices accept comma separated items, maps and structs JSON.
+func parseConfigValue(typ reflect.Type, str string) (*Value, error) {
+	if typ == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			return nil, err
+		}
+		return NewInt64Value(int64(d)), nil
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		return NewStringValue(str), nil
+	case reflect.Bool:
+		if len(str) == 0 {
+			return NewBoolValue(false), nil
+		}
+		b, err := strconv.ParseBool(str)
+		if err != nil {
+			return nil, err
+		}
+		return NewBoolValue(b), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if len(str) == 0 {
+			return NewInt64Value(0), nil
+		}
+		i, err := strconv.ParseInt(str, 10, typ.Bits())
+		if err != nil {
+			return nil, err
+		}
+		return NewInt64Value(i), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if len(str) == 0 {
+			return NewUint64Value(0), nil
+		}
+		u, err := strconv.ParseUint(str, 10, typ.Bits())
+		if err != nil {
+			return nil, err
+		}
+		return NewUint64Value(u), nil
+	case reflect.Float32, reflect.Float64:
+		if len(str) == 0 {
+			return NewFloat64Value(0), nil
+		}
+		f, err := strconv.ParseFloat(str, typ.Bits())
+		if err != nil {
+			return nil, err
+		}
+		return NewFloat64Value(f), nil
+	case reflect.Slice, reflect.Array:
+		vals := make([]*Value, 0)
+		if len(str) > 0 {
+			for _, s := range strings.Split(str, ",") {
+				v, err := parseConfigValue(typ.Elem(), strings.TrimSpace(s))
+				if err != nil {
+					return nil, err
+				}
+				vals = append(vals, v)
+			}
+		}
+		return NewArrayValue(vals...), nil
+	default:
+		if len(str) == 0 {
+			return NewNullValue(), nil
+		}
+		v := &Value{}
+		if err := jsoniter.ConfigFastest.UnmarshalFromString(str, v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+}
+
+func readConfigFile(file string) (*Object, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewObject(), nil
+		}
+		return nil, err
+	}
+	return decodeObjectFile(file, data)
+}
+
+// decodeObjectFile decodes the YAML or JSON data of file, by its extension.
+func decodeObjectFile(file string, data []byte) (*Object, error) {
+	var err error
+	obj := NewObject()
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		m := make(map[string]any)
+		if err = yaml.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", file, err)
+		}
+		if obj, err = NewObjectFromMap(m); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", file, err)
+		}
+	case ".json":
+		if err = jsoniter.ConfigFastest.Unmarshal(data, obj); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", file, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config file format: %s", file)
+	}
+	return obj, nil
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type loaderFileConfig struct {
+	Path    string `json:"path" default:"./app.log"`
+	MaxSize int    `json:"maxSize" default:"100"`
+}
+
+type loaderConfig struct {
+	Name    string            `json:"name" default:"app"`
+	Debug   bool              `json:"debug" default:"true"`
+	Ratio   float64           `json:"ratio" default:"0.5"`
+	Timeout time.Duration     `json:"timeout" default:"3s"`
+	Tags    []string          `json:"tags" default:"a,b"`
+	Labels  map[string]string `json:"labels"`
+	File    loaderFileConfig  `json:"file"`
+}
+
+func TestConfigLoader_Defaults(t *testing.T) {
+	cfg := &loaderConfig{}
+	loader := NewConfigLoader()
+	assert.NoError(t, loader.Load(cfg))
+
+	assert.Equal(t, "app", cfg.Name)
+	assert.True(t, cfg.Debug)
+	assert.Equal(t, 0.5, cfg.Ratio)
+	assert.Equal(t, 3*time.Second, cfg.Timeout)
+	assert.Equal(t, []string{"a", "b"}, cfg.Tags)
+	assert.Equal(t, "./app.log", cfg.File.Path)
+	assert.Equal(t, 100, cfg.File.MaxSize)
+	assert.Equal(t, ConfigSourceDefault, loader.Source("file.maxSize"))
+	assert.Equal(t, ConfigSourceUnset, loader.Source("labels"))
+}
+
+func TestConfigLoader_Layers(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile := filepath.Join(dir, "app.yaml")
+	assert.NoError(t, os.WriteFile(yamlFile, []byte("app:\n  name: from-yaml\n  file:\n    maxSize: 200\n  labels:\n    env: dev\n"), 0o600))
+	jsonFile := filepath.Join(dir, "app.json")
+	assert.NoError(t, os.WriteFile(jsonFile, []byte(`{"app":{"debug":false}}`), 0o600))
+
+	t.Setenv("APP_FILE_PATH", "/var/log/app.log")
+	t.Setenv("APP_TAGS", "x, y, z")
+
+	cfg := &loaderConfig{}
+	loader := NewConfigLoader().
+		WithFile(yamlFile, jsonFile, filepath.Join(dir, "missing.yaml")).
+		WithSection("app").
+		WithEnvPrefix("APP").
+		WithOverride("name", "from-override")
+	assert.NoError(t, loader.Load(cfg))
+
+	assert.Equal(t, "from-override", cfg.Name)
+	assert.False(t, cfg.Debug)
+	assert.Equal(t, 200, cfg.File.MaxSize)
+	assert.Equal(t, "/var/log/app.log", cfg.File.Path)
+	assert.Equal(t, []string{"x", "y", "z"}, cfg.Tags)
+	assert.Equal(t, map[string]string{"env": "dev"}, cfg.Labels)
+
+	assert.Equal(t, ConfigSourceOverride, loader.Source("name"))
+	assert.Equal(t, ConfigSourceFile, loader.Source("debug"))
+	assert.Equal(t, ConfigSourceFile, loader.Source("file.maxSize"))
+	assert.Equal(t, ConfigSourceEnv, loader.Source("file.path"))
+	assert.Equal(t, ConfigSourceDefault, loader.Source("ratio"))
+	assert.Equal(t, "env", loader.Source("tags").String())
+	assert.Equal(t, "from-override", loader.Object().GetString("name"))
+	assert.ErrorIs(t, loader.Object().SetValue("name", NewStringValue("x")), ErrFrozen)
+}
+
+func TestConfigLoader_OverrideOrder(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		cfg := &loaderConfig{}
+		loader := NewConfigLoader().
+			WithOverride("file.maxSize", 300).
+			WithOverride("file", map[string]any{"path": "/tmp/app.log", "maxSize": 200}).
+			WithOverride("file.path", "/var/log/app.log")
+		assert.NoError(t, loader.Load(cfg))
+		assert.Equal(t, loaderFileConfig{Path: "/var/log/app.log", MaxSize: 200}, cfg.File)
+	}
+}
+
+func TestConfigLoader_FileDuration(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.yaml")
+	assert.NoError(t, os.WriteFile(file, []byte("timeout: 5s\nratio: \"0.25\"\n"), 0o600))
+
+	cfg := &loaderConfig{}
+	loader := NewConfigLoader().WithFile(file)
+	assert.NoError(t, loader.Load(cfg))
+	assert.Equal(t, 5*time.Second, cfg.Timeout)
+	assert.Equal(t, 0.25, cfg.Ratio)
+	assert.Equal(t, ConfigSourceFile, loader.Source("timeout"))
+
+	assert.NoError(t, os.WriteFile(file, []byte("timeout: soon\n"), 0o600))
+	assert.Error(t, NewConfigLoader().WithFile(file).Load(&loaderConfig{}))
+}
+
+func TestConfigLoader_Errors(t *testing.T) {
+	assert.Error(t, NewConfigLoader().Load(loaderConfig{}))
+
+	t.Setenv("BAD_FILE_MAX_SIZE", "big")
+	assert.Error(t, NewConfigLoader().WithEnvPrefix("BAD").Load(&loaderConfig{}))
+}
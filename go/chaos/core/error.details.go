@@ -0,0 +1,157 @@
+package core
+
+import (
+	"fmt"
+
+	jsoniter "github.com/json-iterator/go"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrorDetailTypeKey is the member of the object Values of the well-known
+// details holding their type, the full name of their message like
+// "chaos.core.FieldViolation".
+const ErrorDetailTypeKey = "@type"
+
+// ErrorDetail is one of the well-known detail messages, which Error.AddDetail
+// adds as object Values with their type in ErrorDetailTypeKey, so that clients
+// find them by type instead of guessing their shape.
+type ErrorDetail interface {
+	proto.Message
+	isErrorDetail()
+}
+
+func (*FieldViolation) isErrorDetail()   {}
+func (*RetryInfo) isErrorDetail()        {}
+func (*QuotaFailure) isErrorDetail()     {}
+func (*ResourceInfo) isErrorDetail()     {}
+func (*ErrorInfo) isErrorDetail()        {}
+func (*LocalizedMessage) isErrorDetail() {}
+func (*MessageParams) isErrorDetail()    {}
+
+var errorDetailTypes = map[string]func() ErrorDetail{
+	"chaos.core.FieldViolation":   func() ErrorDetail { return &FieldViolation{} },
+	"chaos.core.RetryInfo":        func() ErrorDetail { return &RetryInfo{} },
+	"chaos.core.QuotaFailure":     func() ErrorDetail { return &QuotaFailure{} },
+	"chaos.core.ResourceInfo":     func() ErrorDetail { return &ResourceInfo{} },
+	"chaos.core.ErrorInfo":        func() ErrorDetail { return &ErrorInfo{} },
+	"chaos.core.LocalizedMessage": func() ErrorDetail { return &LocalizedMessage{} },
+	"chaos.core.MessageParams":    func() ErrorDetail { return &MessageParams{} },
+}
+
+// NewErrorDetailValue returns the object Value of detail, its JSON members
+// and its type.
+func NewErrorDetailValue(detail ErrorDetail) (*Value, error) {
+	bs, err := jsoniter.Marshal(detail)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode error detail %s: %w", proto.MessageName(detail), err)
+	}
+	obj := NewObject()
+	if err = obj.UnmarshalJSON(bs); err != nil {
+		return nil, fmt.Errorf("failed to encode error detail %s: %w", proto.MessageName(detail), err)
+	}
+	return NewObjectValue(obj.SetString(ErrorDetailTypeKey, string(proto.MessageName(detail)))), nil
+}
+
+// DecodeErrorDetail returns the well-known detail of v, or nil if v is not
+// one.
+func DecodeErrorDetail(v *Value) (ErrorDetail, error) {
+	obj := v.GetObject()
+	newDetail, ok := errorDetailTypes[obj.GetString(ErrorDetailTypeKey)]
+	if !ok {
+		return nil, nil
+	}
+
+	bs, err := obj.Clone().Delete(ErrorDetailTypeKey).MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	detail := newDetail()
+	if err = jsoniter.Unmarshal(bs, detail); err != nil {
+		return nil, fmt.Errorf("failed to decode error detail %s: %w", proto.MessageName(detail), err)
+	}
+	return detail, nil
+}
+
+// ErrorDetails returns the well-known details of the error, skipping the
+// other details and the ones failing to decode.
+func (e *Error) ErrorDetails() []ErrorDetail {
+	var details []ErrorDetail
+	for _, v := range e.GetDetails() {
+		if detail, err := DecodeErrorDetail(v); err == nil && detail != nil {
+			details = append(details, detail)
+		}
+	}
+	return details
+}
+
+func errorDetailsOf[T ErrorDetail](e *Error) []T {
+	var details []T
+	for _, detail := range e.ErrorDetails() {
+		if d, ok := detail.(T); ok {
+			details = append(details, d)
+		}
+	}
+	return details
+}
+
+func (e *Error) AddFieldViolation(path string, description string) *Error {
+	return e.AddDetail(&FieldViolation{Path: path, Description: description})
+}
+
+func (e *Error) FieldViolations() []*FieldViolation {
+	return errorDetailsOf[*FieldViolation](e)
+}
+
+func (e *Error) AddRetryInfo(retryDelay *Duration) *Error {
+	return e.AddDetail(&RetryInfo{RetryDelay: retryDelay})
+}
+
+// RetryInfo returns the first RetryInfo of the error, or nil.
+func (e *Error) RetryInfo() *RetryInfo {
+	if infos := errorDetailsOf[*RetryInfo](e); len(infos) > 0 {
+		return infos[0]
+	}
+	return nil
+}
+
+func (e *Error) AddQuotaViolation(subject string, description string) *Error {
+	return e.AddDetail(&QuotaFailure{Violations: []*QuotaFailure_Violation{{Subject: subject, Description: description}}})
+}
+
+// QuotaViolations returns the violations of all the QuotaFailures of the
+// error.
+func (e *Error) QuotaViolations() []*QuotaFailure_Violation {
+	var violations []*QuotaFailure_Violation
+	for _, failure := range errorDetailsOf[*QuotaFailure](e) {
+		violations = append(violations, failure.Violations...)
+	}
+	return violations
+}
+
+func (e *Error) AddResourceInfo(resourceType string, resource *Resource, description string) *Error {
+	return e.AddDetail(&ResourceInfo{ResourceType: resourceType, Resource: resource, Description: description})
+}
+
+func (e *Error) ResourceInfos() []*ResourceInfo {
+	return errorDetailsOf[*ResourceInfo](e)
+}
+
+func (e *Error) AddErrorInfo(reason string, domain string, metadata map[string]string) *Error {
+	return e.AddDetail(&ErrorInfo{Reason: reason, Domain: domain, Metadata: metadata})
+}
+
+// ErrorInfo returns the first ErrorInfo of the error, or nil.
+func (e *Error) ErrorInfo() *ErrorInfo {
+	if infos := errorDetailsOf[*ErrorInfo](e); len(infos) > 0 {
+		return infos[0]
+	}
+	return nil
+}
+
+func (e *Error) AddLocalizedMessage(locale string, message string) *Error {
+	return e.AddDetail(&LocalizedMessage{Locale: locale, Message: message})
+}
+
+func (e *Error) LocalizedMessages() []*LocalizedMessage {
+	return errorDetailsOf[*LocalizedMessage](e)
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestError_ErrorDetails(t *testing.T) {
+	err := NewError(InvalidArgument, "invalid user").
+		AddFieldViolation("user.email", "must be an email").
+		AddFieldViolation("user.age", "must be positive").
+		AddRetryInfo(FromDuration(1500*time.Millisecond)).
+		AddQuotaViolation("user:7", "10 requests per minute").
+		AddResourceInfo("user", &Resource{Id: "7", Name: "ann"}, "locked").
+		AddErrorInfo("EMAIL_TAKEN", "users.example.com", map[string]string{"email": "ann@example.com"}).
+		AddLocalizedMessage("en-US", "The email is taken.").
+		AddDetail("plain")
+
+	assert.Equal(t, "chaos.core.FieldViolation", err.Details[0].GetObject().GetString(ErrorDetailTypeKey))
+	assert.Equal(t, "1.5s", err.Details[2].GetObject().GetString("retryDelay"))
+
+	// the details survive the JSON of the error
+	bs, mErr := jsoniter.Marshal(err)
+	assert.NoError(t, mErr)
+	decoded := &Error{}
+	assert.NoError(t, jsoniter.Unmarshal(bs, decoded))
+
+	for _, e := range []*Error{err, decoded} {
+		assert.Len(t, e.ErrorDetails(), 7)
+		assert.True(t, proto.Equal(&FieldViolation{Path: "user.age", Description: "must be positive"}, e.FieldViolations()[1]))
+		assert.Equal(t, 1500*time.Millisecond, e.RetryInfo().GetRetryDelay().ToDuration())
+		assert.Equal(t, "user:7", e.QuotaViolations()[0].Subject)
+		assert.Equal(t, "ann", e.ResourceInfos()[0].GetResource().GetName())
+		assert.Equal(t, map[string]string{"email": "ann@example.com"}, e.ErrorInfo().GetMetadata())
+		assert.Equal(t, "The email is taken.", e.LocalizedMessages()[0].Message)
+	}
+
+	// problem documents list them in "details" instead of merging them
+	bs, mErr = MarshalProblem(err)
+	assert.NoError(t, mErr)
+	problem, uErr := UnmarshalProblem(bs)
+	assert.NoError(t, uErr)
+	assert.Len(t, problem.FieldViolations(), 2)
+	assert.Len(t, problem.Details, 8)
+
+	assert.Nil(t, NewError(NotFound, "").RetryInfo())
+	assert.Nil(t, (*Error)(nil).FieldViolations())
+}
+
+func TestError_TryAddDetail(t *testing.T) {
+	err := NewError(InvalidArgument, "invalid user")
+	assert.Error(t, err.TryAddDetail(make(chan int)))
+	assert.Empty(t, err.Details)
+	assert.Empty(t, err.AddDetail(func() {}).Details)
+
+	assert.NoError(t, err.TryAddDetail(&FieldViolation{Path: "user.age", Description: "must be positive"}))
+	assert.Len(t, err.FieldViolations(), 1)
+
+	var nilErr *Error
+	assert.NoError(t, nilErr.TryAddDetail("plain"))
+}
+
+func TestDecodeErrorDetail(t *testing.T) {
+	detail, err := DecodeErrorDetail(NewObjectValue(NewObject().SetString("path", "a")))
+	assert.NoError(t, err)
+	assert.Nil(t, detail)
+
+	detail, err = DecodeErrorDetail(NewStringValue("a"))
+	assert.NoError(t, err)
+	assert.Nil(t, detail)
+
+	_, err = DecodeErrorDetail(NewObjectValue(NewObject().SetString(ErrorDetailTypeKey, "chaos.core.RetryInfo").SetString("retryDelay", "soon")))
+	assert.Error(t, err)
+}
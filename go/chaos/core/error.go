@@ -4,8 +4,17 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sync/atomic"
 )
 
+var detailRedactor atomic.Pointer[Redactor]
+
+// SetDetailRedactor makes Error.AddDetail redact every detail it adds, e.g.
+// with DefaultRedactor(). A nil Redactor turns redaction off.
+func SetDetailRedactor(r *Redactor) {
+	detailRedactor.Store(r)
+}
+
 func NewError(code *ErrorCode, message string) *Error {
 	return &Error{
 		Code:    code,
@@ -20,9 +29,11 @@ func NewErrorf(code *ErrorCode, format string, arguments ...any) *Error {
 	}
 }
 
+// NewErrorFrom returns an error of the code registered in the default domain,
+// or in the only domain registering it, see RegisterErrorCode.
 func NewErrorFrom(code int32, message string) *Error {
 	err := &Error{Message: message}
-	if ec, ok := errorCodeIndex[code]; ok {
+	if ec, ok := resolveErrorCode(code); ok {
 		err.Code = ec
 	} else {
 		err.Code = &ErrorCode{Code: code}
@@ -34,18 +45,52 @@ func NewFormattedErrorFrom(code int32, format string, arguments ...any) *Error {
 	return NewErrorFrom(code, fmt.Sprintf(format, arguments...))
 }
 
-func (e *Error) Is(err error) bool {
-	return IsError(err)
+// Is reports whether e has the code of target, which is an *ErrorCode like
+// NotFound, an *Error or a typed error like *NotFoundError. Codes match by
+// domain and code, so errors decoded off the wire match too. A target Error
+// without code, as IsError uses, matches any Error.
+func (e *Error) Is(target error) bool {
+	if e == nil {
+		return false
+	}
+	switch t := target.(type) {
+	case *ErrorCode:
+		return e.Code.matches(t)
+	case *Error:
+		return t.GetCode() == nil || e.Code.matches(t.Code)
+	case interface{ errorCode() *ErrorCode }:
+		return e.Code.matches(t.errorCode())
+	}
+	return false
+}
+
+// HasCode reports whether the chain of err has an Error of code, see
+// Error.Is.
+func HasCode(err error, code *ErrorCode) bool {
+	return code != nil && errors.Is(err, code)
 }
 
 func IsError(err error) bool {
 	return errors.Is(err, &Error{})
 }
 
+// AsError returns the first Error in the chain of err, which is an *Error, a
+// *WrappedError or a typed error like *NotFoundError, or nil if the chain has
+// none.
 func AsError(err error) *Error {
-	e := &Error{}
-	if errors.As(err, &e) {
+	switch e := err.(type) {
+	case *Error:
 		return e
+	case interface{ ToError() *Error }:
+		return e.ToError()
+	case interface{ Unwrap() error }:
+		return AsError(e.Unwrap())
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			if ce := AsError(err); ce != nil {
+				return ce
+			}
+		}
 	}
 	return nil
 }
@@ -73,10 +118,34 @@ func (e *Error) StatusCode() int {
 	return http.StatusInternalServerError
 }
 
+// AddDetail adds detail as a Value, the well-known details like
+// *FieldViolation with their type, see ErrorDetail. Details which can not be
+// converted into a Value are dropped, see TryAddDetail.
 func (e *Error) AddDetail(detail any) *Error {
-	if e != nil {
-		v, _ := NewValue(detail)
-		e.Details = append(e.Details, v)
-	}
+	_ = e.TryAddDetail(detail)
 	return e
 }
+
+// TryAddDetail is like AddDetail, but returns the error of converting detail
+// into a Value instead of dropping it.
+func (e *Error) TryAddDetail(detail any) error {
+	if e == nil {
+		return nil
+	}
+
+	var v *Value
+	var err error
+	if d, ok := detail.(ErrorDetail); ok {
+		v, err = NewErrorDetailValue(d)
+	} else {
+		v, err = NewValue(detail)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add error detail: %w", err)
+	}
+	if r := detailRedactor.Load(); r != nil {
+		v = r.RedactValue(v)
+	}
+	e.Details = append(e.Details, v)
+	return nil
+}
@@ -0,0 +1,221 @@
+package core
+
+import (
+	"errors"
+	"strconv"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// GRPCCode is a gRPC canonical status code, with the values of codes.Code of
+// google.golang.org/grpc.
+type GRPCCode uint32
+
+const (
+	GRPCOK                 GRPCCode = 0
+	GRPCCanceled           GRPCCode = 1
+	GRPCUnknown            GRPCCode = 2
+	GRPCInvalidArgument    GRPCCode = 3
+	GRPCDeadlineExceeded   GRPCCode = 4
+	GRPCNotFound           GRPCCode = 5
+	GRPCAlreadyExists      GRPCCode = 6
+	GRPCPermissionDenied   GRPCCode = 7
+	GRPCResourceExhausted  GRPCCode = 8
+	GRPCFailedPrecondition GRPCCode = 9
+	GRPCAborted            GRPCCode = 10
+	GRPCOutOfRange         GRPCCode = 11
+	GRPCUnimplemented      GRPCCode = 12
+	GRPCInternal           GRPCCode = 13
+	GRPCUnavailable        GRPCCode = 14
+	GRPCDataLoss           GRPCCode = 15
+	GRPCUnauthenticated    GRPCCode = 16
+)
+
+var grpcCodeNames = [...]string{
+	"OK", "CANCELLED", "UNKNOWN", "INVALID_ARGUMENT", "DEADLINE_EXCEEDED", "NOT_FOUND", "ALREADY_EXISTS",
+	"PERMISSION_DENIED", "RESOURCE_EXHAUSTED", "FAILED_PRECONDITION", "ABORTED", "OUT_OF_RANGE",
+	"UNIMPLEMENTED", "INTERNAL", "UNAVAILABLE", "DATA_LOSS", "UNAUTHENTICATED",
+}
+
+func (c GRPCCode) String() string {
+	if int(c) < len(grpcCodeNames) {
+		return grpcCodeNames[c]
+	}
+	return "CODE(" + strconv.FormatUint(uint64(c), 10) + ")"
+}
+
+// grpcCodes maps the built-in codes to the gRPC codes, and errorCodesOfGRPC
+// maps them back. The gRPC codes have one built-in code each, while
+// BadRequest and MalformedRequest share INVALID_ARGUMENT with
+// InvalidArgument.
+var (
+	grpcCodes = map[*ErrorCode]GRPCCode{
+		BadRequest:         GRPCInvalidArgument,
+		InvalidArgument:    GRPCInvalidArgument,
+		MalformedRequest:   GRPCInvalidArgument,
+		FailedPrecondition: GRPCFailedPrecondition,
+		OutOfRange:         GRPCOutOfRange,
+		Unauthenticated:    GRPCUnauthenticated,
+		PermissionDenied:   GRPCPermissionDenied,
+		NotFound:           GRPCNotFound,
+		AlreadyExists:      GRPCAlreadyExists,
+		Aborted:            GRPCAborted,
+		ResourceExhausted:  GRPCResourceExhausted,
+		Cancelled:          GRPCCanceled,
+		UnknownError:       GRPCUnknown,
+		InternalError:      GRPCInternal,
+		DataLoss:           GRPCDataLoss,
+		Unimplemented:      GRPCUnimplemented,
+		Unavailable:        GRPCUnavailable,
+		DeadlineExceeded:   GRPCDeadlineExceeded,
+	}
+
+	errorCodesOfGRPC = map[GRPCCode]*ErrorCode{
+		GRPCCanceled:           Cancelled,
+		GRPCUnknown:            UnknownError,
+		GRPCInvalidArgument:    InvalidArgument,
+		GRPCDeadlineExceeded:   DeadlineExceeded,
+		GRPCNotFound:           NotFound,
+		GRPCAlreadyExists:      AlreadyExists,
+		GRPCPermissionDenied:   PermissionDenied,
+		GRPCResourceExhausted:  ResourceExhausted,
+		GRPCFailedPrecondition: FailedPrecondition,
+		GRPCAborted:            Aborted,
+		GRPCOutOfRange:         OutOfRange,
+		GRPCUnimplemented:      Unimplemented,
+		GRPCInternal:           InternalError,
+		GRPCUnavailable:        Unavailable,
+		GRPCDataLoss:           DataLoss,
+		GRPCUnauthenticated:    Unauthenticated,
+	}
+)
+
+// GRPCCodeOf returns the gRPC code of code. The built-in codes have a fixed
+// mapping, other codes are mapped by their HTTP status code like
+// ErrorCodeOfStatus maps it, and a nil code is OK.
+func GRPCCodeOf(code *ErrorCode) GRPCCode {
+	if code == nil {
+		return GRPCOK
+	}
+	for ec, c := range grpcCodes {
+		if ec.matches(code) {
+			return c
+		}
+	}
+
+	status := int(code.HttpStatusCode)
+	if ec, ok := statusErrorCodes[status]; ok {
+		return grpcCodes[ec]
+	}
+	switch {
+	case status >= 400 && status < 500:
+		return GRPCFailedPrecondition
+	case status >= 500 && status < 600:
+		return GRPCInternal
+	}
+	return GRPCUnknown
+}
+
+// ErrorCodeOfGRPC returns the built-in code of a gRPC code, nil for OK and
+// UnknownError for codes out of range.
+func ErrorCodeOfGRPC(code GRPCCode) *ErrorCode {
+	if code == GRPCOK {
+		return nil
+	}
+	if ec, ok := errorCodesOfGRPC[code]; ok {
+		return ec
+	}
+	return UnknownError
+}
+
+// GRPCCode returns the gRPC code of the error, see GRPCCodeOf.
+func (e *Error) GRPCCode() GRPCCode {
+	if e == nil {
+		return GRPCOK
+	}
+	return GRPCCodeOf(e.Code)
+}
+
+// ToStatus converts the error into a Status. Besides the gRPC code and the
+// message, the details hold the ErrorCode, every detail Value and the Error
+// of the cause, so that FromStatus restores them. Status has the wire format
+// of google.rpc.Status, see GRPCStatus.
+func (e *Error) ToStatus() (*Status, error) {
+	if e == nil {
+		return &Status{}, nil
+	}
+
+	status := &Status{Code: int32(e.GRPCCode()), Message: e.Message}
+	if e.Code != nil {
+		detail, err := anypb.New(e.Code)
+		if err != nil {
+			return nil, err
+		}
+		status.Details = append(status.Details, detail)
+	}
+	for _, v := range e.Details {
+		detail, err := anypb.New(v)
+		if err != nil {
+			return nil, err
+		}
+		status.Details = append(status.Details, detail)
+	}
+	if e.Cause != nil {
+		detail, err := anypb.New(e.Cause)
+		if err != nil {
+			return nil, err
+		}
+		status.Details = append(status.Details, detail)
+	}
+	return status, nil
+}
+
+// GRPCStatus returns the status of the error, see ToStatus, by which gRPC
+// servers send the errors their handlers return, including wrapped ones. An
+// error failing to convert is INTERNAL.
+func (e *Error) GRPCStatus() *status.Status {
+	s, err := e.ToStatus()
+	if err != nil {
+		return status.New(codes.Internal, err.Error())
+	}
+	return status.FromProto(&spb.Status{Code: s.Code, Message: s.Message, Details: s.Details})
+}
+
+// FromStatus converts a Status into an Error, nil for OK. Its code is the
+// ErrorCode in the details, or the built-in code of the gRPC code. Details
+// of other types than ErrorCode, Value and Error are dropped.
+func FromStatus(status *Status) (*Error, error) {
+	if status == nil || GRPCCode(status.Code) == GRPCOK {
+		return nil, nil
+	}
+
+	e := &Error{Message: status.Message}
+	for _, detail := range status.Details {
+		m, err := detail.UnmarshalNew()
+		if err != nil {
+			if errors.Is(err, protoregistry.NotFound) {
+				continue
+			}
+			return nil, err
+		}
+		switch m := m.(type) {
+		case *ErrorCode:
+			e.Code = m
+		case *Value:
+			e.Details = append(e.Details, m)
+		case *Error:
+			e.Cause = m
+		}
+	}
+
+	if e.Code == nil {
+		e.Code = ErrorCodeOfGRPC(GRPCCode(status.Code))
+	} else if registered, ok := LookupErrorCode(e.Code.Domain, e.Code.Code); ok {
+		e.Code = registered
+	}
+	return e, nil
+}
@@ -0,0 +1,101 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestGRPCCode(t *testing.T) {
+	for code := GRPCCanceled; code <= GRPCUnauthenticated; code++ {
+		ec := ErrorCodeOfGRPC(code)
+		assert.NotNil(t, ec, code.String())
+		assert.Equal(t, code, GRPCCodeOf(ec), code.String())
+	}
+	for _, ec := range ErrorCodes("") {
+		assert.NotEqual(t, GRPCOK, GRPCCodeOf(ec), ec.Name)
+	}
+
+	assert.Equal(t, GRPCInvalidArgument, GRPCCodeOf(BadRequest))
+	assert.Equal(t, GRPCOK, GRPCCodeOf(nil))
+	assert.Nil(t, ErrorCodeOfGRPC(GRPCOK))
+	assert.Equal(t, UnknownError, ErrorCodeOfGRPC(42))
+	assert.Equal(t, "CODE(42)", GRPCCode(42).String())
+	assert.Equal(t, "NOT_FOUND", GRPCNotFound.String())
+
+	// codes decoded from JSON only have the code
+	assert.Equal(t, GRPCNotFound, NewErrorFrom(404, "x").GRPCCode())
+	assert.Equal(t, GRPCNotFound, (&Error{Code: &ErrorCode{Code: 404}}).GRPCCode())
+
+	// other codes are mapped by their HTTP status code
+	assert.Equal(t, GRPCAlreadyExists, GRPCCodeOf(&ErrorCode{Domain: "test.grpc", Code: 1, HttpStatusCode: 409}))
+	assert.Equal(t, GRPCFailedPrecondition, GRPCCodeOf(&ErrorCode{Domain: "test.grpc", Code: 2, HttpStatusCode: 422}))
+	assert.Equal(t, GRPCInternal, GRPCCodeOf(&ErrorCode{Domain: "test.grpc", Code: 3, HttpStatusCode: 502}))
+	assert.Equal(t, GRPCUnknown, GRPCCodeOf(&ErrorCode{Domain: "test.grpc", Code: 4}))
+}
+
+func TestError_ToStatus(t *testing.T) {
+	err := AsError(Wrap(NewNotFoundError("user 7").ToError(), Unavailable, "load user")).AddDetail(map[string]any{"retry": 3})
+	status, convErr := err.ToStatus()
+	assert.NoError(t, convErr)
+	assert.Equal(t, int32(GRPCUnavailable), status.Code)
+	assert.Equal(t, "load user", status.Message)
+	assert.Len(t, status.Details, 3)
+	assert.Equal(t, "type.googleapis.com/chaos.core.ErrorCode", status.Details[0].TypeUrl)
+
+	bs, convErr := proto.Marshal(status)
+	assert.NoError(t, convErr)
+	received := &Status{}
+	assert.NoError(t, proto.Unmarshal(bs, received))
+
+	decoded, convErr := FromStatus(received)
+	assert.NoError(t, convErr)
+	assert.Same(t, Unavailable, decoded.Code)
+	assert.Equal(t, "load user", decoded.Message)
+	assert.Equal(t, int64(3), decoded.Details[0].GetObject().GetInt64("retry"))
+	assert.True(t, IsNotFoundError(decoded))
+	assert.True(t, IsUnavailableError(decoded))
+
+	nilStatus, convErr := (*Error)(nil).ToStatus()
+	assert.NoError(t, convErr)
+	decoded, convErr = FromStatus(nilStatus)
+	assert.NoError(t, convErr)
+	assert.Nil(t, decoded)
+}
+
+func TestFromStatus_Foreign(t *testing.T) {
+	other, _ := anypb.New(durationpb.New(1))
+	status := &Status{Code: int32(GRPCPermissionDenied), Message: "denied", Details: []*anypb.Any{
+		other,
+		{TypeUrl: "type.googleapis.com/unknown.Type"},
+	}}
+
+	err, convErr := FromStatus(status)
+	assert.NoError(t, convErr)
+	assert.Same(t, PermissionDenied, err.Code)
+	assert.Empty(t, err.Details)
+	assert.Equal(t, "denied", err.Message)
+}
+
+func TestError_GRPCStatus(t *testing.T) {
+	s, ok := status.FromError(NewError(NotFound, "user 7"))
+	assert.True(t, ok)
+	assert.Equal(t, codes.NotFound, s.Code())
+	assert.Equal(t, "user 7", s.Message())
+
+	s, ok = status.FromError(NewAlreadyExistsError("user 7"))
+	assert.True(t, ok)
+	assert.Equal(t, codes.AlreadyExists, s.Code())
+
+	s, ok = status.FromError(fmt.Errorf("load: %w", Wrap(NewNotFoundError("user 7"), Unavailable, "load user")))
+	assert.True(t, ok)
+	assert.Equal(t, codes.Unavailable, s.Code())
+	assert.Equal(t, "load: load user", s.Message())
+	assert.Len(t, s.Details(), 2)
+}
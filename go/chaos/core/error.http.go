@@ -0,0 +1,261 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// maxErrorBodySize limits how much of a response body ErrorFromResponse reads.
+const maxErrorBodySize = 1 << 20
+
+// httpErrorContentTypes are the content types WriteHTTPError offers, the
+// first one is written if the request accepts anything.
+var httpErrorContentTypes = []string{"application/json", ProblemContentType, "text/plain"}
+
+var httpErrorReporter atomic.Pointer[func(r *http.Request, err error)]
+
+// SetHTTPErrorReporter makes WriteHTTPError report the errors of 5xx status
+// codes, including recovered panics, to report, e.g. for logging, as their
+// messages are not written to the response. HTTPHandlerFunc reports the errors
+// returned after the response is written too. A nil report turns it off.
+func SetHTTPErrorReporter(report func(r *http.Request, err error)) {
+	if report == nil {
+		httpErrorReporter.Store(nil)
+		return
+	}
+	httpErrorReporter.Store(&report)
+}
+
+// HTTPHandlerFunc is an http.Handler returning an error, which is written as
+// the response by WriteHTTPError unless the handler has written one already.
+// Panics are recovered into InternalError, except http.ErrAbortHandler.
+type HTTPHandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+func (f HTTPHandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rw := &httpResponseWriter{ResponseWriter: w}
+	defer func() {
+		if p := recover(); p != nil {
+			if p == http.ErrAbortHandler {
+				panic(p)
+			}
+			err, ok := p.(error)
+			if !ok {
+				err = fmt.Errorf("%v", p)
+			}
+			rw.writeError(r, Wrapf(err, InternalError, "panic: %v", p))
+		}
+	}()
+
+	if err := f(rw, r); err != nil {
+		rw.writeError(r, err)
+	}
+}
+
+// httpResponseWriter records whether the handler has written the header.
+type httpResponseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *httpResponseWriter) WriteHeader(statusCode int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *httpResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (w *httpResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *httpResponseWriter) writeError(r *http.Request, err error) {
+	if w.wroteHeader {
+		if report := httpErrorReporter.Load(); report != nil {
+			(*report)(r, err)
+		}
+		return
+	}
+	_ = WriteHTTPError(w.ResponseWriter, r, err)
+}
+
+// WriteHTTPError writes err as the response of r, with the status code of the
+// error and in the content type negotiated by the Accept header of r: JSON of
+// the Error, a problem document, see WriteProblem, or the plain message.
+// Errors having no Error in their chain are InternalError. Errors of 5xx status
+// codes only keep their code, with the status text as message, and are
+// reported, see SetHTTPErrorReporter. Errors get a LocalizedMessage in the
+// language negotiated by the Accept-Language header of r, see Localize.
+func WriteHTTPError(w http.ResponseWriter, r *http.Request, err error) error {
+	e := AsError(err)
+	if e == nil {
+		e = NewError(InternalError, "")
+		if err != nil {
+			e.Message = err.Error()
+		}
+	}
+
+	status := e.StatusCode()
+	if status >= http.StatusInternalServerError {
+		if report := httpErrorReporter.Load(); report != nil {
+			(*report)(r, err)
+		}
+		e = NewError(e.Code, http.StatusText(status))
+	}
+	if r != nil {
+		e = Localize(e, r.Header.Values("Accept-Language")...)
+	}
+
+	contentType := httpErrorContentTypes[0]
+	if r != nil {
+		contentType = negotiateContentType(r.Header.Values("Accept"), httpErrorContentTypes)
+	}
+	if contentType == ProblemContentType {
+		return WriteProblem(w, e)
+	}
+
+	var body []byte
+	switch contentType {
+	case "text/plain":
+		body = []byte(e.Error())
+		contentType = "text/plain; charset=utf-8"
+	default:
+		var mErr error
+		if body, mErr = jsoniter.Marshal(e); mErr != nil {
+			return mErr
+		}
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", contentType)
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Del("Content-Length")
+	w.WriteHeader(status)
+	_, wErr := w.Write(body)
+	return wErr
+}
+
+// negotiateContentType returns the offer of the highest quality in the
+// Accept headers, the first one of equal qualities. The first offer is
+// returned for no Accept header, or if none of the offers is accepted.
+func negotiateContentType(accept []string, offers []string) string {
+	best, bestQuality, bestSpecificity := offers[0], -1.0, -1
+	for _, header := range accept {
+		for _, part := range strings.Split(header, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil {
+				continue
+			}
+			quality := 1.0
+			if q, ok := params["q"]; ok {
+				if quality, err = strconv.ParseFloat(q, 64); err != nil {
+					continue
+				}
+			}
+			if quality <= 0 {
+				continue
+			}
+
+			for _, offer := range offers {
+				specificity := mediaTypeSpecificity(mediaType, offer)
+				if specificity < 0 {
+					continue
+				}
+				if quality > bestQuality || (quality == bestQuality && specificity > bestSpecificity) {
+					best, bestQuality, bestSpecificity = offer, quality, specificity
+				}
+			}
+		}
+	}
+	return best
+}
+
+// mediaTypeSpecificity returns how specifically pattern, like */*, text/* or
+// text/plain, matches mediaType, or -1 if it does not.
+func mediaTypeSpecificity(pattern, mediaType string) int {
+	switch {
+	case pattern == mediaType:
+		return 2
+	case pattern == "*/*":
+		return 0
+	case strings.HasSuffix(pattern, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(pattern, "*")):
+		return 1
+	}
+	return -1
+}
+
+// ErrorFromResponse returns the Error of a non-2xx response, or nil for a 2xx
+// one. Errors written by WriteHTTPError or WriteProblem are decoded with their
+// registered codes, other responses get the built-in code of their status,
+// see ErrorCodeOfStatus, and their text/plain body or status text as
+// message. The body is read, but not closed.
+func ErrorFromResponse(resp *http.Response) *Error {
+	if resp == nil {
+		return NewError(UnknownError, "no response")
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	var body []byte
+	if resp.Body != nil {
+		body, _ = io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+
+	switch {
+	case mediaType == ProblemContentType:
+		p := &Problem{}
+		if err := p.UnmarshalJSON(body); err == nil {
+			if p.Status == 0 {
+				p.Status = resp.StatusCode
+			}
+			return p.ToError()
+		}
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		if e := decodeResponseError(body, resp.StatusCode); e != nil {
+			return e
+		}
+	}
+
+	message := string(bytes.TrimSpace(body))
+	if len(message) == 0 || mediaType != "text/plain" {
+		message = http.StatusText(resp.StatusCode)
+	}
+	return NewError(ErrorCodeOfStatus(resp.StatusCode), message)
+}
+
+// decodeResponseError decodes the JSON of an Error, returning nil for bodies
+// which are not one.
+func decodeResponseError(body []byte, status int) *Error {
+	e := &Error{}
+	if err := jsoniter.Unmarshal(body, e); err != nil || e.Code == nil {
+		return nil
+	}
+	if ec, ok := resolveErrorCode(e.Code.Code); ok {
+		e.Code = ec
+	} else {
+		e.Code.HttpStatusCode = int32(status)
+	}
+	return e
+}
+
+// CheckResponse returns the Error of a non-2xx response as an error, see
+// ErrorFromResponse, and nil otherwise.
+func CheckResponse(resp *http.Response) error {
+	if e := ErrorFromResponse(resp); e != nil {
+		return e
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func serveHTTPError(handler http.Handler, accept string) *http.Response {
+	req := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+	if len(accept) > 0 {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Result()
+}
+
+func TestHTTPHandlerFunc(t *testing.T) {
+	notFound := HTTPHandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return NewNotFoundError("no user %d", 7)
+	})
+
+	resp := serveHTTPError(notFound, "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	err := ErrorFromResponse(resp)
+	assert.True(t, IsNotFoundError(err))
+	assert.Equal(t, "no user 7", err.Message)
+
+	resp = serveHTTPError(notFound, "application/problem+json, application/json;q=0.9")
+	assert.Equal(t, ProblemContentType, resp.Header.Get("Content-Type"))
+	err = ErrorFromResponse(resp)
+	assert.Same(t, NotFound, err.Code)
+	assert.Equal(t, "no user 7", err.Message)
+
+	wrapped := HTTPHandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return fmt.Errorf("load: %w", NewNotFoundError("no user %d", 7))
+	})
+	resp = serveHTTPError(wrapped, "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	err = ErrorFromResponse(resp)
+	assert.Same(t, NotFound, err.Code)
+	assert.Equal(t, "no user 7", err.Message)
+
+	resp = serveHTTPError(notFound, "text/*")
+	assert.Equal(t, "text/plain; charset=utf-8", resp.Header.Get("Content-Type"))
+	err = ErrorFromResponse(resp)
+	assert.Same(t, NotFound, err.Code)
+	assert.Equal(t, "no user 7", err.Message)
+
+	ok := HTTPHandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+	resp = serveHTTPError(ok, "")
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Nil(t, ErrorFromResponse(resp))
+	assert.NoError(t, CheckResponse(resp))
+}
+
+func TestHTTPHandlerFunc_Internal(t *testing.T) {
+	var reported []error
+	SetHTTPErrorReporter(func(r *http.Request, err error) {
+		reported = append(reported, err)
+	})
+	defer SetHTTPErrorReporter(nil)
+
+	failing := HTTPHandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("password=secret")
+	})
+	resp := serveHTTPError(failing, "")
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	err := ErrorFromResponse(resp)
+	assert.True(t, IsInternalError(err))
+	assert.Equal(t, "Internal Server Error", err.Message)
+
+	panicking := HTTPHandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		panic("nil map")
+	})
+	resp = serveHTTPError(panicking, ProblemContentType)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	err = ErrorFromResponse(resp)
+	assert.True(t, IsInternalError(err))
+	assert.Equal(t, "Internal Server Error", err.Message)
+
+	written := HTTPHandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		_, _ = w.Write([]byte("partial"))
+		return NewUnavailableError("stream broken")
+	})
+	resp = serveHTTPError(written, "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Len(t, reported, 3)
+	assert.EqualError(t, reported[0], "password=secret")
+	assert.EqualError(t, reported[1], "panic: nil map")
+	assert.True(t, IsUnavailableError(reported[2]))
+
+	assert.Panics(t, func() {
+		serveHTTPError(HTTPHandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+			panic(http.ErrAbortHandler)
+		}), "")
+	})
+}
+
+func TestErrorFromResponse(t *testing.T) {
+	newResponse := func(status int, contentType, body string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: http.Header{}, Body: http.NoBody}
+		if len(contentType) > 0 {
+			resp.Header.Set("Content-Type", contentType)
+		}
+		if len(body) > 0 {
+			resp.Body = io.NopCloser(strings.NewReader(body))
+		}
+		return resp
+	}
+
+	err := ErrorFromResponse(newResponse(http.StatusConflict, "application/json; charset=utf-8", `{"code": "10", "message": "retry"}`))
+	assert.Same(t, Aborted, err.Code)
+
+	err = ErrorFromResponse(newResponse(http.StatusTeapot, "application/json", `{"code": "600001", "message": "brewing"}`))
+	assert.Equal(t, int32(600001), err.Code.Code)
+	assert.Equal(t, http.StatusTeapot, err.StatusCode())
+
+	err = ErrorFromResponse(newResponse(http.StatusServiceUnavailable, "application/json", `{"error": "down"}`))
+	assert.Same(t, Unavailable, err.Code)
+	assert.Equal(t, "Service Unavailable", err.Message)
+
+	err = ErrorFromResponse(newResponse(http.StatusBadGateway, "text/html", `<html>bad gateway</html>`))
+	assert.Same(t, InternalError, err.Code)
+	assert.Equal(t, "Bad Gateway", err.Message)
+
+	err = ErrorFromResponse(newResponse(http.StatusUnauthorized, "text/plain", "token expired\n"))
+	assert.True(t, IsUnauthenticatedError(err))
+	assert.Equal(t, "token expired", err.Message)
+
+	err = ErrorFromResponse(newResponse(http.StatusForbidden, ProblemContentType, `{"title": "Forbidden"}`))
+	assert.Same(t, PermissionDenied, err.Code)
+}
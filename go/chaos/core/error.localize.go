@@ -0,0 +1,352 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultMessageLanguage is the language a MessageCatalog falls back to when
+// none of the requested ones has a message.
+const DefaultMessageLanguage = "en"
+
+type messageKey struct {
+	name string
+	id   string
+}
+
+// MessageCatalog holds the message templates of error codes per language
+// tag, keyed by the name of the code and a message ID, the empty ID for the
+// default message of the code. Templates refer to the named parameters of
+// the error as {name}, see Error.AddMessageParams.
+type MessageCatalog struct {
+	mu        sync.RWMutex
+	languages map[string]string // lowercase tag -> tag
+	messages  map[string]map[messageKey]string
+	fallback  string
+}
+
+var defaultMessageCatalog atomic.Pointer[MessageCatalog]
+
+func init() {
+	defaultMessageCatalog.Store(NewMessageCatalog())
+}
+
+// DefaultMessageCatalog returns the catalog Localize and WriteHTTPError use.
+func DefaultMessageCatalog() *MessageCatalog {
+	return defaultMessageCatalog.Load()
+}
+
+// SetDefaultMessageCatalog replaces the catalog Localize and WriteHTTPError
+// use, a nil catalog with an empty one.
+func SetDefaultMessageCatalog(c *MessageCatalog) {
+	if c == nil {
+		c = NewMessageCatalog()
+	}
+	defaultMessageCatalog.Store(c)
+}
+
+func NewMessageCatalog() *MessageCatalog {
+	return &MessageCatalog{
+		languages: map[string]string{},
+		messages:  map[string]map[messageKey]string{},
+		fallback:  DefaultMessageLanguage,
+	}
+}
+
+// WithFallback sets the language used when none of the requested ones has a
+// message, DefaultMessageLanguage by default.
+func (c *MessageCatalog) WithFallback(langTag string) *MessageCatalog {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fallback = normalizeLanguageTag(langTag)
+	return c
+}
+
+// Add adds the template of the message id of the code name in langTag.
+func (c *MessageCatalog) Add(langTag string, name string, id string, template string) *MessageCatalog {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tag := normalizeLanguageTag(langTag)
+	lower := strings.ToLower(tag)
+	if _, ok := c.languages[lower]; !ok {
+		c.languages[lower] = tag
+		c.messages[lower] = map[messageKey]string{}
+	}
+	c.messages[lower][messageKey{name: name, id: id}] = template
+	return c
+}
+
+// AddObject adds the messages of obj in langTag. Its keys are the names of
+// the codes, and its values the default messages of the codes, or objects of
+// the templates by message ID:
+//
+//	NOT_FOUND: "The resource is not found."
+//	ALREADY_EXISTS:
+//	  "": "The resource exists already."
+//	  user: "The user {name} exists already."
+func (c *MessageCatalog) AddObject(langTag string, obj *Object) error {
+	for _, name := range sortedKeys(obj.GetVals()) {
+		val := obj.Vals[name]
+		switch val.GetKind() {
+		case ValueKind_VALUE_KIND_STRING:
+			c.Add(langTag, name, "", val.GetString())
+		case ValueKind_VALUE_KIND_OBJECT:
+			ids := val.GetObject()
+			for _, id := range sortedKeys(ids.GetVals()) {
+				template := ids.Vals[id]
+				if template.GetKind() != ValueKind_VALUE_KIND_STRING {
+					return fmt.Errorf("message %s.%s of %s is not a string", name, id, langTag)
+				}
+				c.Add(langTag, name, id, template.GetString())
+			}
+		default:
+			return fmt.Errorf("messages of %s in %s are neither a string nor an object", name, langTag)
+		}
+	}
+	return nil
+}
+
+// LoadFile adds the messages of a JSON or YAML file named by its language
+// tag, like "zh-CN.yaml", see AddObject.
+func (c *MessageCatalog) LoadFile(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	obj, err := decodeObjectFile(file, data)
+	if err != nil {
+		return err
+	}
+	langTag := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	return c.AddObject(langTag, obj)
+}
+
+// LoadDir adds the messages of the JSON and YAML files of dir, see LoadFile.
+func (c *MessageCatalog) LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".json", ".yaml", ".yml":
+			if entry.IsDir() {
+				continue
+			}
+			if err = c.LoadFile(filepath.Join(dir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Languages returns the language tags of the catalog, sorted.
+func (c *MessageCatalog) Languages() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	tags := make([]string, 0, len(c.languages))
+	for _, tag := range c.languages {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// Match returns the languages of the catalog matching langTags best first,
+// and the ones matching the fallback language last. langTags are language
+// tags or Accept-Language headers like "fr-CH, fr;q=0.9, en;q=0.8". A
+// requested tag matches the same tag, then its prefixes like "fr" for
+// "fr-CH", then the tags it prefixes like "fr-FR" for "fr".
+func (c *MessageCatalog) Match(langTags ...string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var matches []string
+	matched := map[string]bool{}
+	add := func(lower string) {
+		if _, ok := c.languages[lower]; ok && !matched[lower] {
+			matched[lower] = true
+			matches = append(matches, c.languages[lower])
+		}
+	}
+
+	for _, requested := range append(parseAcceptLanguage(langTags), c.fallback) {
+		lower := strings.ToLower(requested)
+		for prefix := lower; len(prefix) > 0; prefix = truncateLanguageTag(prefix) {
+			add(prefix)
+		}
+		var extended []string
+		for tag := range c.languages {
+			if strings.HasPrefix(tag, lower+"-") {
+				extended = append(extended, tag)
+			}
+		}
+		sort.Strings(extended)
+		for _, tag := range extended {
+			add(tag)
+		}
+	}
+	return matches
+}
+
+// Localize returns a copy of the Error of err with a LocalizedMessage in the
+// best language of langTags having a message for it, see Match. The message
+// is the template of the code name and the message ID of the MessageParams of
+// the error, rendered with its parameters. The Error itself is returned if
+// there is no such message, and nil if err has no Error.
+func (c *MessageCatalog) Localize(err error, langTags ...string) *Error {
+	e := AsError(err)
+	if e == nil || e.Code == nil {
+		return e
+	}
+
+	key := messageKey{name: e.Code.Name}
+	var params *Object
+	if p := e.MessageParams(); p != nil {
+		key.id = p.Id
+		params = p.Params
+	}
+
+	for _, tag := range c.Match(langTags...) {
+		c.mu.RLock()
+		template, ok := c.messages[strings.ToLower(tag)][key]
+		c.mu.RUnlock()
+		if !ok {
+			continue
+		}
+		for _, m := range e.LocalizedMessages() {
+			if m.Locale == tag {
+				return e
+			}
+		}
+		return proto.Clone(e).(*Error).AddLocalizedMessage(tag, renderMessage(template, params))
+	}
+	return e
+}
+
+// Localize localizes err with DefaultMessageCatalog, see
+// MessageCatalog.Localize.
+func Localize(err error, langTags ...string) *Error {
+	return DefaultMessageCatalog().Localize(err, langTags...)
+}
+
+// renderMessage replaces the {name} placeholders of template with the
+// parameters of params, keeping the ones of missing parameters.
+func renderMessage(template string, params *Object) string {
+	if !strings.Contains(template, "{") {
+		return template
+	}
+
+	b := strings.Builder{}
+	for len(template) > 0 {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		b.WriteString(template[:start])
+		if val := params.GetValue(template[start+1 : end]); val != nil {
+			if val.GetKind() == ValueKind_VALUE_KIND_STRING {
+				b.WriteString(val.GetString())
+			} else {
+				b.WriteString(val.Text())
+			}
+		} else {
+			b.WriteString(template[start : end+1])
+		}
+		template = template[end+1:]
+	}
+	b.WriteString(template)
+	return b.String()
+}
+
+// parseAcceptLanguage returns the tags of Accept-Language headers by
+// descending quality, dropping the ones of quality 0 and "*".
+func parseAcceptLanguage(headers []string) []string {
+	type weightedTag struct {
+		tag     string
+		quality float64
+	}
+	var tags []weightedTag
+	for _, header := range headers {
+		for _, part := range strings.Split(header, ",") {
+			tag, params, _ := strings.Cut(part, ";")
+			tag = normalizeLanguageTag(tag)
+			quality := 1.0
+			if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+				var err error
+				if quality, err = strconv.ParseFloat(q, 64); err != nil {
+					continue
+				}
+			}
+			if len(tag) > 0 && tag != "*" && quality > 0 {
+				tags = append(tags, weightedTag{tag: tag, quality: quality})
+			}
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].quality > tags[j].quality
+	})
+
+	result := make([]string, 0, len(tags))
+	for _, t := range tags {
+		result = append(result, t.tag)
+	}
+	return result
+}
+
+func normalizeLanguageTag(tag string) string {
+	return strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+}
+
+// truncateLanguageTag removes the last subtag of tag, and the single letter
+// subtag before it, like RFC 4647 lookup does.
+func truncateLanguageTag(tag string) string {
+	i := strings.LastIndexByte(tag, '-')
+	if i < 0 {
+		return ""
+	}
+	tag = tag[:i]
+	if i = strings.LastIndexByte(tag, '-'); i >= 0 && i == len(tag)-2 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+// AddMessageParams adds the MessageParams of the error, the message ID and
+// the named parameters of its message in the catalogs, see Localize.
+// Parameters which are not convertible into Values are kept as strings.
+func (e *Error) AddMessageParams(id string, params map[string]any) *Error {
+	obj := NewObject()
+	for name, param := range params {
+		val, err := NewValue(param)
+		if err != nil {
+			val = NewStringValue(fmt.Sprint(param))
+		}
+		obj.SetValue(name, val)
+	}
+	return e.AddDetail(&MessageParams{Id: id, Params: obj})
+}
+
+// MessageParams returns the first MessageParams of the error, or nil.
+func (e *Error) MessageParams() *MessageParams {
+	if params := errorDetailsOf[*MessageParams](e); len(params) > 0 {
+		return params[0]
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageCatalog_Match(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "en.yaml"), []byte(`
+NOT_FOUND: "The resource is not found."
+ALREADY_EXISTS:
+  "": "The resource exists already."
+  user: "The user {name} exists already, {count} times {unknown}."
+`), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "zh-CN.json"), []byte(`{
+  "NOT_FOUND": "资源不存在。",
+  "ALREADY_EXISTS": {"user": "用户 {name} 已存在。"}
+}`), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# messages"), 0o600))
+
+	catalog := NewMessageCatalog()
+	assert.NoError(t, catalog.LoadDir(dir))
+	catalog.Add("zh-Hant-TW", "NOT_FOUND", "", "資源不存在。")
+	assert.Equal(t, []string{"en", "zh-CN", "zh-Hant-TW"}, catalog.Languages())
+
+	assert.Equal(t, []string{"zh-CN", "zh-Hant-TW", "en"}, catalog.Match("zh-CN,zh;q=0.9"))
+	assert.Equal(t, []string{"zh-CN", "en"}, catalog.Match("zh-CN"))
+	assert.Equal(t, []string{"zh-CN", "zh-Hant-TW", "en"}, catalog.Match("zh"))
+	assert.Equal(t, []string{"zh-CN", "zh-Hant-TW", "en"}, catalog.Match("fr-CH, fr;q=0.9, zh;q=0.5, *;q=0.1"))
+	assert.Equal(t, []string{"en"}, catalog.Match("en-GB"))
+	assert.Equal(t, []string{"en"}, catalog.Match("zh;q=0, de"))
+	assert.Equal(t, []string{"zh-Hant-TW", "en"}, catalog.Match("zh_hant_tw"))
+	assert.Equal(t, []string{"zh-CN"}, catalog.WithFallback("zh-CN").Match("de"))
+}
+
+func TestLocalize(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "en.yaml"), []byte(`
+NOT_FOUND: "The resource is not found."
+ALREADY_EXISTS:
+  "": "The resource exists already."
+  user: "The user {name} exists already, {count} times {unknown}."
+`), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "zh-CN.json"), []byte(`{
+  "NOT_FOUND": "资源不存在。",
+  "ALREADY_EXISTS": {"user": "用户 {name} 已存在。"}
+}`), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# messages"), 0o600))
+
+	catalog := NewMessageCatalog()
+	assert.NoError(t, catalog.LoadDir(dir))
+
+	err := NewError(AlreadyExists, "user ann exists").AddMessageParams("user", map[string]any{"name": "ann", "count": 2})
+	localized := catalog.Localize(err, "zh-CN")
+	assert.Equal(t, "user ann exists", localized.Message)
+	assert.Equal(t, []*LocalizedMessage{{Locale: "zh-CN", Message: "用户 ann 已存在。"}}, localized.LocalizedMessages())
+	assert.Empty(t, err.LocalizedMessages())
+
+	// the parameters are kept for clients rendering again
+	params := localized.MessageParams()
+	assert.Equal(t, "user", params.Id)
+	assert.Equal(t, int64(2), params.Params.GetInt64("count"))
+
+	localized = catalog.Localize(err, "fr")
+	assert.Equal(t, "The user ann exists already, 2 times {unknown}.", localized.LocalizedMessages()[0].Message)
+
+	// zh-CN has no default message of ALREADY_EXISTS
+	localized = catalog.Localize(NewError(AlreadyExists, ""), "zh-CN")
+	assert.Equal(t, "en", localized.LocalizedMessages()[0].Locale)
+
+	localized = catalog.Localize(NewNotFoundError("no user 7"), "zh")
+	assert.Equal(t, "资源不存在。", localized.LocalizedMessages()[0].Message)
+
+	unknown := NewError(Aborted, "aborted")
+	assert.Same(t, unknown, catalog.Localize(unknown, "en"))
+	assert.Nil(t, catalog.Localize(os.ErrNotExist, "en"))
+}
+
+func TestWriteHTTPError_Localize(t *testing.T) {
+	old := DefaultMessageCatalog()
+	t.Cleanup(func() { SetDefaultMessageCatalog(old) })
+	SetDefaultMessageCatalog(NewMessageCatalog().Add("de", "NOT_FOUND", "", "Nicht gefunden."))
+
+	req := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+	req.Header.Set("Accept-Language", "de-DE,de;q=0.9")
+	rec := httptest.NewRecorder()
+	assert.NoError(t, WriteHTTPError(rec, req, NewNotFoundError("no user 7")))
+
+	err := ErrorFromResponse(rec.Result())
+	assert.Equal(t, "no user 7", err.Message)
+	assert.Equal(t, "Nicht gefunden.", err.LocalizedMessages()[0].Message)
+
+	localized := Localize(fmt.Errorf("load: %w", NewNotFoundError("no user 7")), "de")
+	assert.Equal(t, "no user 7", localized.Message)
+	assert.Equal(t, "Nicht gefunden.", localized.LocalizedMessages()[0].Message)
+}
+
+func TestMessageCatalog_LoadFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "en.json")
+	assert.NoError(t, os.WriteFile(file, []byte(`{"NOT_FOUND": 1}`), 0o600))
+	assert.Error(t, NewMessageCatalog().LoadFile(file))
+	assert.Error(t, NewMessageCatalog().LoadFile(filepath.Join(t.TempDir(), "de.yaml")))
+}
@@ -0,0 +1,337 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const (
+	// ProblemContentType is the media type of RFC 9457 problem documents.
+	ProblemContentType = "application/problem+json"
+
+	// ProblemBlankType is the type of problems having no further semantics
+	// than their status code.
+	ProblemBlankType = "about:blank"
+)
+
+// the members of a problem holding the code of the Error, and its details
+// other than objects
+const (
+	problemCodeMember    = "code"
+	problemDomainMember  = "domain"
+	problemDetailsMember = "details"
+)
+
+// standardProblemMembers are the members of RFC 9457, which extension members
+// can not override.
+var standardProblemMembers = map[string]bool{
+	"type":     true,
+	"title":    true,
+	"status":   true,
+	"detail":   true,
+	"instance": true,
+}
+
+// isProblemMember reports whether name is a standard member or one holding
+// the code of the Error, which object details can not take.
+func isProblemMember(name string) bool {
+	return standardProblemMembers[name] || name == problemCodeMember || name == problemDomainMember || name == problemDetailsMember
+}
+
+// Problem is a problem details document of RFC 9457, which REST APIs return
+// as application/problem+json.
+type Problem struct {
+	Type     string
+	Title    string
+	Status   int
+	Detail   string
+	Instance string
+
+	// Extensions holds the extension members, which are written after the
+	// standard members sorted by name.
+	Extensions *Object
+}
+
+// ToProblem converts the error into a Problem:
+//
+//   - type is the document Url of the code, or about:blank
+//   - title is the name of the code, its description or the status text
+//   - status is StatusCode()
+//   - detail is the message
+//
+// The code and domain of the code are the extension members "code" and
+// "domain". The members of object details become extension members too,
+// unless their names are taken, and other details, including the well-known
+// ones, are listed in "details".
+func (e *Error) ToProblem() *Problem {
+	if e == nil {
+		return nil
+	}
+
+	p := &Problem{
+		Type:       ProblemBlankType,
+		Status:     e.StatusCode(),
+		Detail:     e.Message,
+		Extensions: NewObject(),
+	}
+	if code := e.Code; code != nil {
+		if doc := code.CatalogDocument(); doc != nil {
+			p.Type = doc.Format()
+		}
+		p.Title = code.Name
+		if len(p.Title) == 0 {
+			p.Title = code.Description
+		}
+		p.Extensions.SetInt32(problemCodeMember, code.Code)
+		if len(code.Domain) > 0 {
+			p.Extensions.SetString(problemDomainMember, code.Domain)
+		}
+	}
+	if len(p.Title) == 0 {
+		p.Title = http.StatusText(p.Status)
+	}
+
+	var others []*Value
+	for _, detail := range e.Details {
+		obj := detail.GetObject()
+		if obj == nil || obj.GetValue(ErrorDetailTypeKey) != nil {
+			others = append(others, detail)
+			continue
+		}
+		for _, key := range sortedKeys(obj.Vals) {
+			if _, taken := p.Extensions.Vals[key]; !taken && !isProblemMember(key) {
+				p.Extensions.SetValue(key, obj.Vals[key])
+			}
+		}
+	}
+	if len(others) > 0 {
+		p.Extensions.SetValue(problemDetailsMember, NewArrayValue(others...))
+	}
+	return p
+}
+
+// ToError converts the problem back into an Error. Its code is the one
+// registered for the "code" and "domain" members, or for the title as name,
+// or the built-in code of the status, see ErrorCodeOfStatus. Problems of
+// unregistered codes get a code made of the members. The extension members
+// become an object detail, and the items of "details" follow it.
+func (p *Problem) ToError() *Error {
+	if p == nil {
+		return nil
+	}
+
+	e := &Error{Code: p.errorCode(), Message: p.Detail}
+	ext := p.Extensions.GetVals()
+	object := NewObject()
+	for key, val := range ext {
+		if !isProblemMember(key) {
+			object.SetValue(key, val)
+		}
+	}
+	if !object.IsEmpty() {
+		e.Details = append(e.Details, NewObjectValue(object))
+	}
+	e.Details = append(e.Details, ext[problemDetailsMember].GetValues()...)
+	return e
+}
+
+func (p *Problem) errorCode() *ErrorCode {
+	domain := p.Extensions.GetString(problemDomainMember)
+	codeVal := p.Extensions.GetValue(problemCodeMember)
+	hasCode := codeVal.GetKind() == ValueKind_VALUE_KIND_INTEGER
+	if hasCode {
+		if ec, ok := LookupErrorCode(domain, int32(codeVal.GetInt64())); ok {
+			return ec
+		}
+	} else if ec, ok := LookupErrorCodeByName(domain, p.Title); ok {
+		return ec
+	}
+	if !hasCode && len(domain) == 0 {
+		return ErrorCodeOfStatus(p.Status)
+	}
+
+	code := &ErrorCode{
+		Code:           int32(codeVal.GetInt64()),
+		Name:           p.Title,
+		Domain:         domain,
+		HttpStatusCode: int32(p.Status),
+	}
+	if len(p.Type) > 0 && p.Type != ProblemBlankType {
+		code.Document, _ = ParseUrl(p.Type)
+	}
+	return code
+}
+
+// statusErrorCodes maps the HTTP status codes with a built-in code of their
+// own to it, for ErrorCodeOfStatus and GRPCCodeOf.
+var statusErrorCodes = map[int]*ErrorCode{
+	http.StatusBadRequest:         BadRequest,
+	http.StatusUnauthorized:       Unauthenticated,
+	http.StatusForbidden:          PermissionDenied,
+	http.StatusNotFound:           NotFound,
+	http.StatusConflict:           AlreadyExists,
+	http.StatusTooManyRequests:    ResourceExhausted,
+	499:                           Cancelled,
+	http.StatusNotImplemented:     Unimplemented,
+	http.StatusServiceUnavailable: Unavailable,
+	http.StatusGatewayTimeout:     DeadlineExceeded,
+}
+
+// ErrorCodeOfStatus returns the built-in code of an HTTP status code. Other
+// 4xx codes are BadRequest, and other codes are InternalError.
+func ErrorCodeOfStatus(status int) *ErrorCode {
+	if code, ok := statusErrorCodes[status]; ok {
+		return code
+	}
+	if status >= 400 && status < 500 {
+		return BadRequest
+	}
+	return InternalError
+}
+
+// MarshalJSON writes the standard members which are set, then the extension
+// members.
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	stream := jsoniter.ConfigDefault.BorrowStream(nil)
+	defer jsoniter.ConfigDefault.ReturnStream(stream)
+
+	stream.WriteObjectStart()
+	more := false
+	writeString := func(name, val string) {
+		if len(val) == 0 {
+			return
+		}
+		if more {
+			stream.WriteMore()
+		}
+		stream.WriteObjectField(name)
+		stream.WriteString(val)
+		more = true
+	}
+	writeString("type", p.Type)
+	writeString("title", p.Title)
+	if p.Status != 0 {
+		if more {
+			stream.WriteMore()
+		}
+		stream.WriteObjectField("status")
+		stream.WriteInt(p.Status)
+		more = true
+	}
+	writeString("detail", p.Detail)
+	writeString("instance", p.Instance)
+
+	ext := p.Extensions.GetVals()
+	keys := make([]string, 0, len(ext))
+	for key := range ext {
+		if !standardProblemMembers[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if more {
+			stream.WriteMore()
+		}
+		writeObjectField(stream, key)
+		writeValue(stream, ext[key])
+		more = true
+	}
+	stream.WriteObjectEnd()
+
+	if stream.Error != nil {
+		return nil, stream.Error
+	}
+	return append([]byte(nil), stream.Buffer()...), nil
+}
+
+// UnmarshalJSON reads a problem document. Standard members of the wrong type
+// are ignored as RFC 9457 requires, and the type defaults to about:blank.
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	obj := &Object{}
+	if err := obj.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	if obj.Vals == nil {
+		return errors.New("problem document must be a JSON object")
+	}
+
+	*p = Problem{Type: ProblemBlankType, Extensions: NewObject()}
+	for key, val := range obj.Vals {
+		switch key {
+		case "type":
+			if val.GetKind() == ValueKind_VALUE_KIND_STRING && len(val.GetString()) > 0 {
+				p.Type = val.GetString()
+			}
+		case "title":
+			p.Title = val.GetString()
+		case "status":
+			if val.GetKind() == ValueKind_VALUE_KIND_INTEGER {
+				p.Status = int(val.GetInt64())
+			}
+		case "detail":
+			p.Detail = val.GetString()
+		case "instance":
+			p.Instance = val.GetString()
+		default:
+			p.Extensions.SetValue(key, val)
+		}
+	}
+	return nil
+}
+
+// MarshalProblem encodes err as a problem document.
+func MarshalProblem(err *Error) ([]byte, error) {
+	if err == nil {
+		return nil, errors.New("nil error has no problem document")
+	}
+	return err.ToProblem().MarshalJSON()
+}
+
+// UnmarshalProblem decodes a problem document into an Error.
+func UnmarshalProblem(data []byte) (*Error, error) {
+	p := &Problem{}
+	if err := p.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("failed to decode problem document: %w", err)
+	}
+	return p.ToError(), nil
+}
+
+// WriteProblem writes err as an application/problem+json response with the
+// status code of the error. Errors having no Error in their chain are
+// InternalError. Errors of 5xx status codes only keep their code, with the
+// status text as detail, like WriteHTTPError writes them.
+func WriteProblem(w http.ResponseWriter, err error) error {
+	e := AsError(err)
+	if e == nil {
+		e = NewError(InternalError, "")
+	}
+	if status := e.StatusCode(); status >= http.StatusInternalServerError {
+		e = NewError(e.Code, http.StatusText(status))
+	}
+	p := e.ToProblem()
+	body, mErr := p.MarshalJSON()
+	if mErr != nil {
+		return mErr
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", ProblemContentType)
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Del("Content-Length")
+	w.WriteHeader(p.Status)
+	_, wErr := w.Write(body)
+	return wErr
+}
+
+// ProblemHandler returns a handler responding every request with err as a
+// problem document, see WriteProblem.
+func ProblemHandler(err error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = WriteProblem(w, err)
+	})
+}
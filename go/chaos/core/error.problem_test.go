@@ -0,0 +1,98 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError_ToProblem(t *testing.T) {
+	doc, _ := ParseUrl("https://errors.example.com/orders/out-of-stock")
+	outOfStock := &ErrorCode{Domain: "test.problem", Code: 7001, Name: "OUT_OF_STOCK", Document: doc, HttpStatusCode: 409}
+	MustRegister(outOfStock)
+
+	err := NewError(outOfStock, "sku 42 is sold out").
+		AddDetail(map[string]any{"sku": "42", "title": "ignored"}).
+		AddDetail("try tomorrow")
+	bs, mErr := MarshalProblem(err)
+	assert.NoError(t, mErr)
+	assert.JSONEq(t, `{
+		"type": "https://errors.example.com/orders/out-of-stock",
+		"title": "OUT_OF_STOCK",
+		"status": 409,
+		"detail": "sku 42 is sold out",
+		"code": 7001,
+		"domain": "test.problem",
+		"sku": "42",
+		"details": ["try tomorrow"]
+	}`, string(bs))
+
+	decoded, uErr := UnmarshalProblem(bs)
+	assert.NoError(t, uErr)
+	assert.Same(t, outOfStock, decoded.Code)
+	assert.Equal(t, "sku 42 is sold out", decoded.Message)
+	assert.Len(t, decoded.Details, 2)
+	assert.Equal(t, "42", decoded.Details[0].GetObject().GetString("sku"))
+	assert.Equal(t, "try tomorrow", decoded.Details[1].GetString())
+
+	p := NewError(nil, "boom").ToProblem()
+	assert.Equal(t, ProblemBlankType, p.Type)
+	assert.Equal(t, "Internal Server Error", p.Title)
+	assert.Equal(t, 500, p.Status)
+	assert.Nil(t, (*Error)(nil).ToProblem())
+}
+
+func TestUnmarshalProblem(t *testing.T) {
+	err, uErr := UnmarshalProblem([]byte(`{"title": "Not Found", "status": 404, "detail": "no user 7", "instance": "/users/7"}`))
+	assert.NoError(t, uErr)
+	assert.Same(t, NotFound, err.Code)
+	assert.Equal(t, "no user 7", err.Message)
+
+	err, uErr = UnmarshalProblem([]byte(`{"type": "https://example.com/probs/out-of-credit", "title": "You do not have enough credit.",
+		"status": 403, "code": 12, "domain": "test.bank", "balance": 30}`))
+	assert.NoError(t, uErr)
+	assert.Equal(t, int32(12), err.Code.Code)
+	assert.Equal(t, "test.bank", err.Code.Domain)
+	assert.Equal(t, 403, err.StatusCode())
+	assert.Equal(t, "https://example.com/probs/out-of-credit", err.Code.Document.Format())
+	assert.Equal(t, int64(30), err.Details[0].GetObject().GetInt64("balance"))
+
+	// members of the wrong type are ignored
+	p := &Problem{}
+	assert.NoError(t, p.UnmarshalJSON([]byte(`{"type": 1, "status": "400"}`)))
+	assert.Equal(t, ProblemBlankType, p.Type)
+	assert.Equal(t, 0, p.Status)
+
+	_, uErr = UnmarshalProblem([]byte(`[]`))
+	assert.Error(t, uErr)
+}
+
+func TestWriteProblem(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ProblemHandler(NewNotFoundError("no user 7")).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/7", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, ProblemContentType, rec.Header().Get("Content-Type"))
+	assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+	assert.JSONEq(t, `{"type": "about:blank", "title": "NOT_FOUND", "status": 404, "detail": "no user 7", "code": 404}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	assert.NoError(t, WriteProblem(rec, fmt.Errorf("load: %w", NewNotFoundError("no user 7"))))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.JSONEq(t, `{"type": "about:blank", "title": "NOT_FOUND", "status": 404, "detail": "no user 7", "code": 404}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	assert.NoError(t, WriteProblem(rec, errors.New("disk full")))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	err, _ := UnmarshalProblem(rec.Body.Bytes())
+	assert.True(t, HasCode(err, InternalError))
+	assert.Equal(t, http.StatusText(http.StatusInternalServerError), err.Message)
+
+	rec = httptest.NewRecorder()
+	assert.NoError(t, WriteProblem(rec, NewError(Unavailable, "db 10.0.0.7 down").AddDetail(map[string]any{"host": "10.0.0.7"})))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.JSONEq(t, `{"type": "about:blank", "title": "UNAVAILABLE", "status": 503, "detail": "Service Unavailable", "code": 503}`, rec.Body.String())
+}
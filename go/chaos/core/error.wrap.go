@@ -0,0 +1,234 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+
+	"google.golang.org/grpc/status"
+)
+
+var errorStackTrace atomic.Bool
+
+// SetErrorStackTrace makes Wrap and Wrapf capture the stack trace where the
+// error is created, which %+v prints. It is off by default, as capturing
+// costs about a microsecond.
+func SetErrorStackTrace(enabled bool) {
+	errorStackTrace.Store(enabled)
+}
+
+// WrappedError is an Error caused by a Go error, as Wrap returns it. It keeps
+// the cause as it is, so that errors.Is and errors.As reach it, and the stack
+// trace where it is created. Neither survives a copy: ToError returns the
+// Error for the wire, whose Cause field holds the cause chain.
+type WrappedError struct {
+	err   *Error
+	cause error
+	stack []uintptr
+}
+
+// Wrap returns a *WrappedError of code and message caused by cause, or nil if
+// cause is nil. The cause is kept as it is for Unwrap, and converted into the
+// Cause field of the Error for the wire: an *Error is kept, other errors
+// become an Error of their message without code. AsError returns the Error.
+func Wrap(cause error, code *ErrorCode, message string) error {
+	if cause == nil {
+		return nil
+	}
+	return wrap(cause, NewError(code, message))
+}
+
+// Wrapf is like Wrap with a formatted message.
+func Wrapf(cause error, code *ErrorCode, format string, arguments ...any) error {
+	if cause == nil {
+		return nil
+	}
+	return wrap(cause, NewErrorf(code, format, arguments...))
+}
+
+func wrap(cause error, e *Error) *WrappedError {
+	e.Cause = toCauseError(cause)
+	w := &WrappedError{err: e, cause: cause}
+	if errorStackTrace.Load() {
+		pcs := make([]uintptr, 32)
+		// skip runtime.Callers, wrap and Wrap
+		w.stack = pcs[:runtime.Callers(3, pcs)]
+	}
+	return w
+}
+
+func toCauseError(err error) *Error {
+	if e := toChainError(err); e != nil {
+		return e
+	}
+	return &Error{Message: err.Error()}
+}
+
+func (w *WrappedError) Error() string {
+	return w.ToError().Error()
+}
+
+// ToError returns the Error for the wire, carrying the cause chain in its
+// Cause field.
+func (w *WrappedError) ToError() *Error {
+	if w == nil {
+		return nil
+	}
+	return w.err
+}
+
+func (w *WrappedError) StatusCode() int {
+	return w.ToError().StatusCode()
+}
+
+// GRPCStatus returns the status of the Error, see Error.GRPCStatus.
+func (w *WrappedError) GRPCStatus() *status.Status {
+	return w.ToError().GRPCStatus()
+}
+
+// AddDetail adds detail to the Error, see Error.AddDetail.
+func (w *WrappedError) AddDetail(detail any) *WrappedError {
+	w.ToError().AddDetail(detail)
+	return w
+}
+
+// TryAddDetail adds detail to the Error, see Error.TryAddDetail.
+func (w *WrappedError) TryAddDetail(detail any) error {
+	return w.ToError().TryAddDetail(detail)
+}
+
+// Unwrap returns the error wrapped by Wrap.
+func (w *WrappedError) Unwrap() error {
+	if w == nil {
+		return nil
+	}
+	return w.cause
+}
+
+// Is reports whether the Error has the code of target, see Error.Is.
+func (w *WrappedError) Is(target error) bool {
+	return w.ToError().Is(target)
+}
+
+// As sets target to the Error if it is an **Error, so that errors.As finds
+// the Error rather than an *Error among the causes.
+func (w *WrappedError) As(target any) bool {
+	if t, ok := target.(**Error); ok && w != nil {
+		*t = w.err
+		return true
+	}
+	return false
+}
+
+// StackTrace returns the frames captured by Wrap, see SetErrorStackTrace.
+func (w *WrappedError) StackTrace() []runtime.Frame {
+	if w == nil || len(w.stack) == 0 {
+		return nil
+	}
+	var frames []runtime.Frame
+	callers := runtime.CallersFrames(w.stack)
+	for {
+		frame, more := callers.Next()
+		frames = append(frames, frame)
+		if !more {
+			return frames
+		}
+	}
+}
+
+// Format implements fmt.Formatter like Error.Format does, printing the stack
+// trace captured with %+v.
+func (w *WrappedError) Format(f fmt.State, verb rune) {
+	formatError(f, verb, w)
+}
+
+// Unwrap returns the Cause field, the cause of an error received off the
+// wire.
+func (e *Error) Unwrap() error {
+	if cause := e.GetCause(); cause != nil {
+		return cause
+	}
+	return nil
+}
+
+// Format implements fmt.Formatter: %v and %s print Error, and %+v prints
+// the code and message of every error of the chain, with the stack traces
+// captured.
+func (e *Error) Format(f fmt.State, verb rune) {
+	formatError(f, verb, e)
+}
+
+func formatError(f fmt.State, verb rune, err error) {
+	switch {
+	case verb == 'v' && f.Flag('+'):
+		formatChain(f, err)
+	case verb == 'v' || verb == 's':
+		_, _ = io.WriteString(f, err.Error())
+	case verb == 'q':
+		_, _ = io.WriteString(f, strconv.Quote(err.Error()))
+	default:
+		_, _ = fmt.Fprintf(f, "%%!%c(%T=%s)", verb, err, err.Error())
+	}
+}
+
+func formatChain(w io.Writer, err error) {
+	for i := 0; err != nil; i++ {
+		if i > 0 {
+			_, _ = io.WriteString(w, "\ncaused by: ")
+		}
+
+		ce := toChainError(err)
+		if ce == nil {
+			// a foreign error prints its own chain
+			_, _ = fmt.Fprintf(w, "%+v", err)
+			return
+		}
+		if name := ce.GetCode().codeLabel(); len(name) > 0 {
+			_, _ = fmt.Fprintf(w, "%s: ", name)
+		}
+		_, _ = io.WriteString(w, ce.GetMessage())
+		if st, ok := err.(interface{ StackTrace() []runtime.Frame }); ok {
+			for _, frame := range st.StackTrace() {
+				_, _ = fmt.Fprintf(w, "\n    %s\n        %s:%d", frame.Function, frame.File, frame.Line)
+			}
+		}
+		err = errors.Unwrap(err)
+	}
+}
+
+// toChainError returns err as an *Error if it is one, a *WrappedError or one
+// of the typed errors like *NotFoundError.
+func toChainError(err error) *Error {
+	switch e := err.(type) {
+	case *Error:
+		return e
+	case interface{ ToError() *Error }:
+		return e.ToError()
+	}
+	return nil
+}
+
+// codeLabel returns the name of the code, or its number if it has no name.
+func (x *ErrorCode) codeLabel() string {
+	switch {
+	case x == nil:
+		return ""
+	case len(x.Name) > 0:
+		return x.Name
+	default:
+		return strconv.Itoa(int(x.Code))
+	}
+}
+
+// Unwrap returns the cause of the error, see Error.Unwrap.
+func (e *basicError) Unwrap() error {
+	return (*Error)(e).Unwrap()
+}
+
+// Format implements fmt.Formatter like Error.Format does.
+func (e *basicError) Format(f fmt.State, verb rune) {
+	formatError(f, verb, e)
+}
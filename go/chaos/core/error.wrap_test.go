@@ -0,0 +1,98 @@
+package core
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestWrap(t *testing.T) {
+	assert.True(t, Wrap(nil, InternalError, "query user") == nil)
+	assert.True(t, Wrapf(nil, InternalError, "query user %d", 7) == nil)
+
+	err := Wrap(sql.ErrNoRows, InternalError, "query user")
+	assert.Equal(t, "query user", err.Error())
+	assert.True(t, errors.Is(err, sql.ErrNoRows))
+	assert.Equal(t, sql.ErrNoRows, errors.Unwrap(err))
+	assert.Equal(t, sql.ErrNoRows.Error(), AsError(err).Cause.Message)
+	assert.Nil(t, AsError(err).Cause.Code)
+	assert.True(t, HasCode(err, InternalError))
+	assert.Same(t, err.(*WrappedError).ToError(), AsError(err))
+
+	outer := Wrapf(fmt.Errorf("repository: %w", err), UnknownError, "load user %d", 7)
+	assert.True(t, errors.Is(outer, sql.ErrNoRows))
+	var inner *WrappedError
+	assert.True(t, errors.As(errors.Unwrap(outer), &inner))
+	assert.Same(t, err, inner)
+
+	typed := Wrap(NewNotFoundError("user %d", 7), InternalError, "load user")
+	assert.Equal(t, NotFound, AsError(typed).Cause.Code)
+	assert.Equal(t, "user 7", AsError(typed).Cause.Message)
+}
+
+func TestWrap_Copy(t *testing.T) {
+	err := Wrap(NewNotFoundError("user 7").ToError(), InternalError, "load user")
+
+	cloned := proto.Clone(AsError(err)).(*Error)
+	assert.True(t, IsNotFoundError(cloned))
+	assert.Equal(t, "user 7", errors.Unwrap(cloned).Error())
+
+	copied := &Error{}
+	assert.NoError(t, jsoniter.UnmarshalFromString(`{"code":"500","message":"load user"}`, copied))
+	assert.Nil(t, errors.Unwrap(copied))
+
+	localized := NewMessageCatalog().Add("en", "INTERNAL_ERROR", "", "internal").Localize(err, "en")
+	assert.True(t, IsNotFoundError(localized))
+	assert.Equal(t, "user 7", errors.Unwrap(localized).Error())
+}
+
+func TestWrap_Wire(t *testing.T) {
+	err := Wrap(Wrap(sql.ErrNoRows, NotFound, "no user"), InternalError, "load user")
+
+	bs, marshalErr := proto.Marshal(AsError(err))
+	assert.NoError(t, marshalErr)
+	decoded := &Error{}
+	assert.NoError(t, proto.Unmarshal(bs, decoded))
+	assert.Equal(t, "no user", decoded.Cause.Message)
+	assert.Equal(t, sql.ErrNoRows.Error(), decoded.Cause.Cause.Message)
+
+	var cause *Error
+	assert.True(t, errors.As(errors.Unwrap(decoded), &cause))
+	assert.Equal(t, "no user", cause.Message)
+
+	s, marshalErr := jsoniter.MarshalToString(AsError(err))
+	assert.NoError(t, marshalErr)
+	assert.Equal(t, `{"code":"500","message":"load user","cause":{"code":"404","message":"no user","cause":{"message":"sql: no rows in result set"}}}`, s)
+}
+
+func TestError_Format(t *testing.T) {
+	err := Wrap(Wrap(fmt.Errorf("dial: %w", errors.New("refused")), Unavailable, "connect db"), InternalError, "load user")
+	assert.Equal(t, "load user", fmt.Sprintf("%v", err))
+	assert.Equal(t, `"load user"`, fmt.Sprintf("%q", err))
+	assert.Equal(t, "INTERNAL_ERROR: load user\ncaused by: UNAVAILABLE: connect db\ncaused by: dial: refused", fmt.Sprintf("%+v", err))
+	assert.Equal(t, "NOT_FOUND: user 7", fmt.Sprintf("%+v", NewNotFoundError("user %d", 7)))
+	assert.Equal(t, "600121001: business", fmt.Sprintf("%+v", NewErrorFrom(600121001, "business")))
+	assert.Equal(t, "INTERNAL_ERROR: load user\ncaused by: UNAVAILABLE: connect db\ncaused by: dial: refused", fmt.Sprintf("%+v", AsError(err)))
+}
+
+func TestWrap_StackTrace(t *testing.T) {
+	assert.Nil(t, Wrap(sql.ErrNoRows, InternalError, "query").(*WrappedError).StackTrace())
+
+	SetErrorStackTrace(true)
+	defer SetErrorStackTrace(false)
+	err := Wrap(sql.ErrNoRows, InternalError, "query")
+	frames := err.(*WrappedError).StackTrace()
+	assert.NotEmpty(t, frames)
+	assert.True(t, strings.HasSuffix(frames[0].Function, "TestWrap_StackTrace"), frames[0].Function)
+
+	formatted := fmt.Sprintf("%+v", err)
+	assert.True(t, strings.HasPrefix(formatted, "INTERNAL_ERROR: query\n    "), formatted)
+	assert.Contains(t, formatted, "error.wrap_test.go:")
+	assert.True(t, strings.HasSuffix(formatted, "caused by: sql: no rows in result set"), formatted)
+}
@@ -0,0 +1,283 @@
+package core
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// The formats WriteErrorCatalog writes.
+const (
+	ErrorCatalogMarkdown = "markdown"
+	ErrorCatalogHTML     = "html"
+	ErrorCatalogJSON     = "json"
+)
+
+// ErrorCatalogEntry is an ErrorCode as the catalogs list it.
+type ErrorCatalogEntry struct {
+	Code        int32  `json:"code"`
+	Name        string `json:"name"`
+	Domain      string `json:"domain,omitempty"`
+	Description string `json:"description,omitempty"`
+	HttpStatus  int    `json:"httpStatus"`
+	Document    string `json:"document,omitempty"`
+	Anchor      string `json:"-"`
+}
+
+// NewErrorCatalogEntry returns the entry of code, its HTTP status being
+// InternalServerError if code has none, as Error.StatusCode does.
+func NewErrorCatalogEntry(code *ErrorCode) *ErrorCatalogEntry {
+	return &ErrorCatalogEntry{
+		Code:        code.GetCode(),
+		Name:        code.GetName(),
+		Domain:      code.GetDomain(),
+		Description: code.GetDescription(),
+		HttpStatus:  (&Error{Code: code}).StatusCode(),
+		Document:    code.CatalogDocument().Format(),
+		Anchor:      ErrorCodeAnchor(code),
+	}
+}
+
+// ErrorCodeAnchor returns the anchor of code in the catalogs, its domain and
+// name in kebab case like "not-found" or "orders-example-com-out-of-stock",
+// which stays the same as long as the code keeps its domain and name.
+func ErrorCodeAnchor(code *ErrorCode) string {
+	s := code.GetName()
+	if len(code.GetDomain()) > 0 {
+		s = code.GetDomain() + "-" + s
+	}
+
+	b := strings.Builder{}
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// ErrorCodeDocument returns the Url of the entry of code in the catalog
+// published at baseURL, baseURL with the anchor of code as fragment.
+func ErrorCodeDocument(baseURL string, code *ErrorCode) (*Url, error) {
+	u, err := ParseUrl(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid error catalog url %q: %w", baseURL, err)
+	}
+	u.Fragment = ErrorCodeAnchor(code)
+	return u, nil
+}
+
+// SetErrorCodeDocuments sets the documents of the codes registered in
+// domains, or in all the domains if none is given, to their entries in the
+// catalog published at baseURL, see ErrorCodeDocument. Codes having a
+// Document keep it. The codes themselves are not modified, the documents are
+// returned by CatalogDocument.
+func SetErrorCodeDocuments(baseURL string, domains ...string) error {
+	if _, err := ParseUrl(baseURL); err != nil {
+		return fmt.Errorf("invalid error catalog url %q: %w", baseURL, err)
+	}
+
+	codes := ErrorCodes(domains...)
+	errorCodes.mu.Lock()
+	defer errorCodes.mu.Unlock()
+	for _, code := range codes {
+		if code.Document == nil {
+			errorCodes.documents[errorCodeKey{domain: code.Domain, code: code.Code}], _ = ErrorCodeDocument(baseURL, code)
+		}
+	}
+	return nil
+}
+
+// CatalogDocument returns the Document of the code, or the entry of the code
+// in the catalog set by SetErrorCodeDocuments.
+func (x *ErrorCode) CatalogDocument() *Url {
+	if x == nil || x.Document != nil {
+		return x.GetDocument()
+	}
+	errorCodes.mu.RLock()
+	defer errorCodes.mu.RUnlock()
+	return errorCodes.documents[errorCodeKey{domain: x.Domain, code: x.Code}]
+}
+
+// WriteErrorCatalog writes the catalog of codes in format, one of
+// ErrorCatalogMarkdown, ErrorCatalogHTML and ErrorCatalogJSON. The markdown
+// and HTML catalogs list the codes by domain in the order given, see
+// ErrorCodes.
+func WriteErrorCatalog(w io.Writer, format string, codes []*ErrorCode) error {
+	entries := make([]*ErrorCatalogEntry, 0, len(codes))
+	for _, code := range codes {
+		entries = append(entries, NewErrorCatalogEntry(code))
+	}
+
+	switch strings.ToLower(format) {
+	case ErrorCatalogMarkdown, "md":
+		return writeErrorCatalogMarkdown(w, entries)
+	case ErrorCatalogHTML:
+		return errorCatalogHTML.Execute(w, groupErrorCatalog(entries))
+	case ErrorCatalogJSON:
+		bs, err := jsoniter.ConfigCompatibleWithStandardLibrary.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(bs, '\n'))
+		return err
+	}
+	return fmt.Errorf("unsupported error catalog format: %s", format)
+}
+
+type errorCatalogDomain struct {
+	Name    string
+	Anchor  string
+	Entries []*ErrorCatalogEntry
+}
+
+func groupErrorCatalog(entries []*ErrorCatalogEntry) []*errorCatalogDomain {
+	var domains []*errorCatalogDomain
+	for _, entry := range entries {
+		if len(domains) == 0 || domains[len(domains)-1].Name != errorCatalogDomainName(entry.Domain) {
+			domains = append(domains, &errorCatalogDomain{
+				Name:   errorCatalogDomainName(entry.Domain),
+				Anchor: "domain-" + ErrorCodeAnchor(&ErrorCode{Name: errorCatalogDomainName(entry.Domain)}),
+			})
+		}
+		domain := domains[len(domains)-1]
+		domain.Entries = append(domain.Entries, entry)
+	}
+	return domains
+}
+
+func errorCatalogDomainName(domain string) string {
+	if len(domain) == 0 {
+		return "default"
+	}
+	return domain
+}
+
+func writeErrorCatalogMarkdown(w io.Writer, entries []*ErrorCatalogEntry) error {
+	domains := groupErrorCatalog(entries)
+	b := strings.Builder{}
+	b.WriteString("# Error Codes\n<a name=\"top\"></a>\n\n## Table of Contents\n\n")
+	for _, domain := range domains {
+		fmt.Fprintf(&b, "- [%s](#%s)\n", domain.Name, domain.Anchor)
+	}
+
+	for _, domain := range domains {
+		fmt.Fprintf(&b, "\n\n\n<a name=\"%s\"></a>\n<p align=\"right\"><a href=\"#top\">Top</a></p>\n\n## %s\n\n", domain.Anchor, domain.Name)
+		b.WriteString("| Code | Name | HTTP Status | Description |\n| ---- | ---- | ----------- | ----------- |\n")
+		for _, entry := range domain.Entries {
+			fmt.Fprintf(&b, "| <a name=\"%s\"></a>%d | %s | %s | %s |\n", entry.Anchor, entry.Code,
+				markdownCell(entry.Name), errorCatalogStatus(entry.HttpStatus), markdownCell(entry.Description))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var markdownCellReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "|", "\\|", "\n", "<br/>")
+
+// markdownCell escapes s for a markdown table cell.
+func markdownCell(s string) string {
+	return markdownCellReplacer.Replace(s)
+}
+
+// errorCatalogStatus returns the status code with its text, like "404 Not
+// Found".
+func errorCatalogStatus(status int) string {
+	text := http.StatusText(status)
+	if status == 499 {
+		// not a standard status, introduced by nginx
+		text = "Client Closed Request"
+	}
+	return strings.TrimSpace(fmt.Sprintf("%d %s", status, text))
+}
+
+var errorCatalogHTML = template.Must(template.New("errors").Funcs(template.FuncMap{
+	"status": errorCatalogStatus,
+}).Parse(`<!DOCTYPE html>
+
+<html>
+  <head>
+    <title>Error Codes</title>
+    <meta charset="UTF-8">
+    <style>
+      body {
+        width: 60em;
+        margin: 1em auto;
+        color: #222;
+        font-family: sans-serif;
+        padding-bottom: 4em;
+      }
+
+      h1, h2 {
+        border-bottom: 1px solid #aaa;
+        padding-bottom: 0.5ex;
+      }
+
+      a {
+        text-decoration: none;
+        color: #567e25;
+      }
+
+      table {
+        width: 100%;
+        font-size: 80%;
+        border-collapse: collapse;
+      }
+
+      thead {
+        font-weight: 700;
+        background-color: #dcdcdc;
+      }
+
+      tbody tr:nth-child(even) {
+        background-color: #fbfbfb;
+      }
+
+      td {
+        border: 1px solid #ccc;
+        padding: 0.5ex 2ex;
+      }
+    </style>
+  </head>
+  <body>
+    <h1 id="title">Error Codes</h1>
+
+    <h2>Table of Contents</h2>
+    <ul>
+      {{- range .}}
+      <li><a href="#{{.Anchor}}">{{.Name}}</a></li>
+      {{- end}}
+    </ul>
+    {{- range .}}
+
+    <h2 id="{{.Anchor}}">{{.Name}}</h2>
+    <table>
+      <thead>
+        <tr><td>Code</td><td>Name</td><td>HTTP Status</td><td>Description</td></tr>
+      </thead>
+      <tbody>
+        {{- range .Entries}}
+        <tr id="{{.Anchor}}">
+          <td>{{.Code}}</td>
+          <td>{{.Name}}</td>
+          <td>{{status .HttpStatus}}</td>
+          <td>{{.Description}}</td>
+        </tr>
+        {{- end}}
+      </tbody>
+    </table>
+    {{- end}}
+  </body>
+</html>
+`))
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteErrorCatalog(t *testing.T) {
+	MustRegister(
+		&ErrorCode{Domain: "test.errorcatalog", Code: 2, Name: "PIPE_NAME", Description: "a | b <c>", HttpStatusCode: 422},
+		&ErrorCode{Domain: "test.errorcatalog", Code: 1, Name: "OUT_OF_STOCK", Description: "The item is sold out.", HttpStatusCode: 409},
+	)
+	codes := ErrorCodes("test.errorcatalog")
+
+	b := &strings.Builder{}
+	assert.NoError(t, WriteErrorCatalog(b, ErrorCatalogMarkdown, codes))
+	assert.Contains(t, b.String(), "- [test.errorcatalog](#domain-test-errorcatalog)\n")
+	assert.Contains(t, b.String(), "| <a name=\"test-errorcatalog-out-of-stock\"></a>1 | OUT_OF_STOCK | 409 Conflict | The item is sold out. |\n")
+	assert.Contains(t, b.String(), "| a \\| b &lt;c&gt; |\n")
+	assert.Less(t, strings.Index(b.String(), "OUT_OF_STOCK"), strings.Index(b.String(), "PIPE_NAME"))
+
+	b.Reset()
+	assert.NoError(t, WriteErrorCatalog(b, ErrorCatalogHTML, codes))
+	assert.Contains(t, b.String(), `<tr id="test-errorcatalog-out-of-stock">`)
+	assert.Contains(t, b.String(), "<td>a | b &lt;c&gt;</td>")
+	assert.Contains(t, b.String(), "<td>422 Unprocessable Entity</td>")
+
+	b.Reset()
+	assert.NoError(t, WriteErrorCatalog(b, ErrorCatalogJSON, codes))
+	var entries []*ErrorCatalogEntry
+	assert.NoError(t, jsoniter.UnmarshalFromString(b.String(), &entries))
+	assert.Equal(t, &ErrorCatalogEntry{Code: 1, Name: "OUT_OF_STOCK", Domain: "test.errorcatalog", Description: "The item is sold out.", HttpStatus: 409}, entries[0])
+
+	assert.Error(t, WriteErrorCatalog(b, "pdf", codes))
+}
+
+func TestSetErrorCodeDocuments(t *testing.T) {
+	doc, _ := ParseUrl("https://example.com/own")
+	MustRegister(
+		&ErrorCode{Domain: "test.documents", Code: 1, Name: "OUT_OF_STOCK"},
+		&ErrorCode{Domain: "test.documents", Code: 2, Name: "DOCUMENTED", Document: doc},
+	)
+
+	assert.NoError(t, SetErrorCodeDocuments("https://docs.example.com/errors.html", "test.documents"))
+	outOfStock, _ := LookupErrorCode("test.documents", 1)
+	assert.Equal(t, "https://docs.example.com/errors.html#test-documents-out-of-stock", outOfStock.CatalogDocument().Format())
+	assert.Nil(t, outOfStock.Document)
+	documented, _ := LookupErrorCode("test.documents", 2)
+	assert.Equal(t, "https://example.com/own", documented.CatalogDocument().Format())
+	assert.Nil(t, NotFound.CatalogDocument())
+
+	// the problem documents and catalog entries of the errors link the entries
+	assert.Equal(t, outOfStock.CatalogDocument().Format(), NewError(outOfStock, "").ToProblem().Type)
+	assert.Equal(t, outOfStock.CatalogDocument().Format(), NewErrorCatalogEntry(outOfStock).Document)
+
+	// decoded codes share the documents of the registered ones
+	decoded := &ErrorCode{Domain: "test.documents", Code: 1}
+	assert.Equal(t, outOfStock.CatalogDocument(), decoded.CatalogDocument())
+
+	assert.Equal(t, "not-found", ErrorCodeAnchor(NotFound))
+}
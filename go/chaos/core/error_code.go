@@ -4,20 +4,30 @@ import (
 	"bytes"
 	"fmt"
 	"strconv"
+
+	"google.golang.org/protobuf/proto"
 )
 
+// NewErrorCode returns a copy of the code registered in the default domain,
+// or in the only domain registering it, see RegisterErrorCode.
 func NewErrorCode(code int32) *ErrorCode {
-	if ec, ok := errorCodeIndex[code]; ok {
-		return &ErrorCode{
-			Code:           ec.Code,
-			Name:           ec.Name,
-			Description:    ec.Description,
-			HttpStatusCode: ec.HttpStatusCode,
-		}
+	if ec, ok := resolveErrorCode(code); ok {
+		return proto.Clone(ec).(*ErrorCode)
 	}
 	return &ErrorCode{Code: code}
 }
 
+// Error returns the name of the code, ErrorCode is an error only to be a
+// target of errors.Is, see Error.Is.
+func (x *ErrorCode) Error() string {
+	return x.codeLabel()
+}
+
+// matches reports whether x and code have the same domain and code.
+func (x *ErrorCode) matches(code *ErrorCode) bool {
+	return x != nil && code != nil && x.Code == code.Code && x.Domain == code.Domain
+}
+
 func ParseErrorCode(code string) (*ErrorCode, error) {
 	ec := &ErrorCode{}
 	err := ec.Parse(code)
@@ -4,6 +4,7 @@ import (
 	"unsafe"
 
 	jsoniter "github.com/json-iterator/go"
+	"google.golang.org/protobuf/proto"
 )
 
 func init() {
@@ -18,17 +19,27 @@ type ErrorCodeStringCodec struct {
 	IsFieldPointer bool
 }
 
+// Decode reads the number of the code. As the JSON form has no domain, the
+// code registered in the default domain or in the only domain registering
+// it is used, see NewErrorFrom, so that the decoded error matches it.
 func (codec *ErrorCodeStringCodec) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
-	s := iter.ReadString()
-	errorCode := codec.errorCode(ptr)
-	if errorCode == nil {
-		errorCode = &ErrorCode{}
-		*(**ErrorCode)(ptr) = errorCode
+	errorCode := &ErrorCode{}
+	if err := errorCode.Parse(iter.ReadString()); err != nil {
+		iter.ReportError("ErrorCodeStringCodec", err.Error())
+		return
+	}
+	if registered, ok := resolveErrorCode(errorCode.Code); ok {
+		errorCode = registered
 	}
 
-	if err := errorCode.Parse(s); err != nil {
-		iter.ReportError("ErrorCodeStringCodec", err.Error())
+	if codec.IsFieldPointer {
+		// point the field to the code instead of overwriting the one it points
+		// to, which may be a registered code
+		*(**ErrorCode)(ptr) = errorCode
+		return
 	}
+	proto.Reset((*ErrorCode)(ptr))
+	proto.Merge((*ErrorCode)(ptr), errorCode)
 }
 
 func (codec *ErrorCodeStringCodec) IsEmpty(ptr unsafe.Pointer) bool {
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	// ErrDuplicateErrorCode is returned by RegisterErrorCode for a code equal
+	// to one registered already.
+	ErrDuplicateErrorCode = errors.New("duplicate error code")
+	// ErrConflictingErrorCode is returned by RegisterErrorCode for a code
+	// whose code or name is registered with different fields in its domain.
+	ErrConflictingErrorCode = errors.New("conflicting error code")
+)
+
+type errorCodeKey struct {
+	domain string
+	code   int32
+}
+
+type errorNameKey struct {
+	domain string
+	name   string
+}
+
+// errorCodeRegistry indexes the registered ErrorCodes by (domain, code) and
+// (domain, name), and by code alone across the domains. It holds the
+// documents set by SetErrorCodeDocuments apart from the codes, which are
+// shared and read without locking.
+type errorCodeRegistry struct {
+	mu        sync.RWMutex
+	byCode    map[errorCodeKey]*ErrorCode
+	byName    map[errorNameKey]*ErrorCode
+	codes     map[int32][]*ErrorCode
+	documents map[errorCodeKey]*Url
+}
+
+var errorCodes = &errorCodeRegistry{
+	byCode:    map[errorCodeKey]*ErrorCode{},
+	byName:    map[errorNameKey]*ErrorCode{},
+	codes:     map[int32][]*ErrorCode{},
+	documents: map[errorCodeKey]*Url{},
+}
+
+// RegisterErrorCode registers code in its domain, so that NewErrorFrom,
+// NewErrorCode and the lookups resolve it. The built-in codes are registered
+// in the default domain "". The code is kept as it is and must not be
+// modified afterward.
+func RegisterErrorCode(code *ErrorCode) error {
+	if code == nil || len(code.Name) == 0 {
+		return errors.New("error code needs a name to be registered")
+	}
+
+	r := errorCodes
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	codeKey := errorCodeKey{domain: code.Domain, code: code.Code}
+	nameKey := errorNameKey{domain: code.Domain, name: code.Name}
+	if existing, ok := r.byCode[codeKey]; ok {
+		if existing == code || proto.Equal(existing, code) {
+			return fmt.Errorf("%w %s", ErrDuplicateErrorCode, code.qualifiedName())
+		}
+		return fmt.Errorf("%w %s: code %d is registered as %s", ErrConflictingErrorCode, code.qualifiedName(), code.Code, existing.qualifiedName())
+	}
+	if existing, ok := r.byName[nameKey]; ok {
+		return fmt.Errorf("%w %s: name %s is registered as %s", ErrConflictingErrorCode, code.qualifiedName(), code.Name, existing.qualifiedName())
+	}
+
+	r.byCode[codeKey] = code
+	r.byName[nameKey] = code
+	r.codes[code.Code] = append(r.codes[code.Code], code)
+	return nil
+}
+
+// MustRegister is like RegisterErrorCode for several codes but panics on
+// errors, for package initialization.
+func MustRegister(codes ...*ErrorCode) {
+	for _, code := range codes {
+		if err := RegisterErrorCode(code); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// LookupErrorCode returns the code registered in domain.
+func LookupErrorCode(domain string, code int32) (*ErrorCode, bool) {
+	errorCodes.mu.RLock()
+	defer errorCodes.mu.RUnlock()
+	ec, ok := errorCodes.byCode[errorCodeKey{domain: domain, code: code}]
+	return ec, ok
+}
+
+// LookupErrorCodeByName returns the code registered in domain by its name.
+func LookupErrorCodeByName(domain string, name string) (*ErrorCode, bool) {
+	errorCodes.mu.RLock()
+	defer errorCodes.mu.RUnlock()
+	ec, ok := errorCodes.byName[errorNameKey{domain: domain, name: name}]
+	return ec, ok
+}
+
+// resolveErrorCode returns the code registered in the default domain, or in
+// the only domain registering it.
+func resolveErrorCode(code int32) (*ErrorCode, bool) {
+	errorCodes.mu.RLock()
+	defer errorCodes.mu.RUnlock()
+	if ec, ok := errorCodes.byCode[errorCodeKey{code: code}]; ok {
+		return ec, true
+	}
+	if codes := errorCodes.codes[code]; len(codes) == 1 {
+		return codes[0], true
+	}
+	return nil, false
+}
+
+// ErrorCodes returns the catalog of the codes registered in domains, or in
+// all the domains if none is given, sorted by domain and code.
+func ErrorCodes(domains ...string) []*ErrorCode {
+	errorCodes.mu.RLock()
+	codes := make([]*ErrorCode, 0, len(errorCodes.byCode))
+	for key, code := range errorCodes.byCode {
+		if len(domains) == 0 || slices.Contains(domains, key.domain) {
+			codes = append(codes, code)
+		}
+	}
+	errorCodes.mu.RUnlock()
+
+	sort.Slice(codes, func(i, j int) bool {
+		if codes[i].Domain != codes[j].Domain {
+			return codes[i].Domain < codes[j].Domain
+		}
+		return codes[i].Code < codes[j].Code
+	})
+	return codes
+}
+
+// qualifiedName returns the code as {domain}.{code}.{name}, without the
+// domain for the default one.
+func (x *ErrorCode) qualifiedName() string {
+	if len(x.GetDomain()) == 0 {
+		return fmt.Sprintf("%d.%s", x.GetCode(), x.GetName())
+	}
+	return fmt.Sprintf("%s.%d.%s", x.GetDomain(), x.GetCode(), x.GetName())
+}
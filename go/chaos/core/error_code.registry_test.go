@@ -0,0 +1,60 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterErrorCode(t *testing.T) {
+	outOfStock := &ErrorCode{Domain: "test.orders", Code: 600121001, Name: "OUT_OF_STOCK", HttpStatusCode: 409}
+	assert.NoError(t, RegisterErrorCode(outOfStock))
+
+	ec, ok := LookupErrorCode("test.orders", 600121001)
+	assert.True(t, ok)
+	assert.Same(t, outOfStock, ec)
+	ec, ok = LookupErrorCodeByName("test.orders", "OUT_OF_STOCK")
+	assert.True(t, ok)
+	assert.Same(t, outOfStock, ec)
+	_, ok = LookupErrorCodeByName("", "OUT_OF_STOCK")
+	assert.False(t, ok)
+
+	// resolved by code alone as it is registered in one domain only
+	assert.Same(t, outOfStock, NewErrorFrom(600121001, "no stock").Code)
+	assert.Equal(t, "OUT_OF_STOCK", NewErrorCode(600121001).Name)
+	assert.Equal(t, "test.orders", NewErrorCode(600121001).Domain)
+
+	err := RegisterErrorCode(&ErrorCode{Domain: "test.orders", Code: 600121001, Name: "OUT_OF_STOCK", HttpStatusCode: 409})
+	assert.True(t, errors.Is(err, ErrDuplicateErrorCode), err)
+	err = RegisterErrorCode(&ErrorCode{Domain: "test.orders", Code: 600121001, Name: "SOLD_OUT"})
+	assert.True(t, errors.Is(err, ErrConflictingErrorCode), err)
+	err = RegisterErrorCode(&ErrorCode{Domain: "test.orders", Code: 600121002, Name: "OUT_OF_STOCK"})
+	assert.True(t, errors.Is(err, ErrConflictingErrorCode), err)
+	assert.Error(t, RegisterErrorCode(&ErrorCode{Domain: "test.orders", Code: 600121003}))
+
+	// the same code in another domain makes resolving by code ambiguous
+	assert.NoError(t, RegisterErrorCode(&ErrorCode{Domain: "test.payments", Code: 600121001, Name: "OUT_OF_STOCK"}))
+	assert.Empty(t, NewErrorFrom(600121001, "no stock").Code.Name)
+
+	// built-in codes keep resolving in the default domain
+	assert.Same(t, NotFound, NewErrorFrom(404, "missing").Code)
+	assert.Panics(t, func() { MustRegister(&ErrorCode{Code: 404, Name: "MISSING"}) })
+}
+
+func TestErrorCodes(t *testing.T) {
+	MustRegister(
+		&ErrorCode{Domain: "test.catalog", Code: 2, Name: "B"},
+		&ErrorCode{Domain: "test.catalog", Code: 1, Name: "A"},
+	)
+
+	codes := ErrorCodes("test.catalog")
+	assert.Len(t, codes, 2)
+	assert.Equal(t, "A", codes[0].Name)
+	assert.Equal(t, "B", codes[1].Name)
+
+	builtins := ErrorCodes("")
+	assert.Len(t, builtins, 18)
+	assert.Equal(t, UnknownError, builtins[0])
+	assert.GreaterOrEqual(t, len(ErrorCodes()), 20)
+}
@@ -2,23 +2,8 @@ package core
 
 import "net/http"
 
-var (
-	errorCodeIndex     = map[int32]*ErrorCode{}
-	errorCodeNameIndex = map[string]*ErrorCode{}
-)
-
 func init() {
-	errorCodeIndex = map[int32]*ErrorCode{}
-	errorCodeNameIndex = map[string]*ErrorCode{}
-
-	addIndex := func(codes ...*ErrorCode) {
-		for _, code := range codes {
-			errorCodeIndex[code.Code] = code
-			errorCodeNameIndex[code.Name] = code
-		}
-	}
-
-	addIndex(BadRequest, InvalidArgument, MalformedRequest, FailedPrecondition, OutOfRange, Unauthenticated,
+	MustRegister(BadRequest, InvalidArgument, MalformedRequest, FailedPrecondition, OutOfRange, Unauthenticated,
 		PermissionDenied, NotFound, AlreadyExists, Aborted, ResourceExhausted, Cancelled, UnknownError,
 		InternalError, DataLoss, Unimplemented, Unavailable, DeadlineExceeded)
 }
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"testing"
 
@@ -16,3 +18,17 @@ func TestErrorStatusCodeFallsBackToInternalServerError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, NewErrorFrom(600121001, "business error").StatusCode())
 	assert.Equal(t, http.StatusInternalServerError, (*Error)(nil).StatusCode())
 }
+
+func TestAsError(t *testing.T) {
+	notFound := NewNotFoundError("user 7")
+	assert.Same(t, notFound.ToError(), AsError(fmt.Errorf("load: %w", notFound)))
+	assert.Same(t, notFound.ToError(), AsError(errors.Join(errors.New("x"), notFound)))
+
+	err := NewError(InternalError, "load user")
+	assert.Same(t, err, AsError(fmt.Errorf("load: %w", err)))
+	wrapped := Wrap(notFound, InternalError, "load user")
+	assert.Same(t, wrapped.(*WrappedError).ToError(), AsError(fmt.Errorf("load: %w", wrapped)))
+
+	assert.Nil(t, AsError(errors.New("x")))
+	assert.Nil(t, AsError(nil))
+}
@@ -1,8 +1,6 @@
 package core
 
-import (
-	"errors"
-)
+import "google.golang.org/grpc/status"
 
 type basicError Error
 
@@ -25,6 +23,14 @@ func (e *basicError) StatusCode() int {
 	return (*Error)(e).StatusCode()
 }
 
+func (e *basicError) GRPCStatus() *status.Status {
+	return (*Error)(e).GRPCStatus()
+}
+
+func (e *basicError) Is(target error) bool {
+	return (*Error)(e).Is(target)
+}
+
 func (e *basicError) AddDetail(detail any) *basicError {
 	return (*basicError)((*Error)(e).AddDetail(detail))
 }
@@ -38,12 +44,11 @@ func NewBadRequestError(format string, args ...any) *BadRequestError {
 }
 
 func IsBadRequestError(err error) bool {
-	return errors.Is(err, &BadRequestError{})
+	return HasCode(err, BadRequest)
 }
 
-func (*BadRequestError) Is(err error) bool {
-	var badRequestError *BadRequestError
-	return errors.As(err, &badRequestError)
+func (*BadRequestError) errorCode() *ErrorCode {
+	return BadRequest
 }
 
 type InvalidArgumentError struct {
@@ -55,12 +60,11 @@ func NewInvalidArgumentError(format string, args ...any) *InvalidArgumentError {
 }
 
 func IsInvalidArgumentError(err error) bool {
-	return errors.Is(err, &InvalidArgumentError{})
+	return HasCode(err, InvalidArgument)
 }
 
-func (*InvalidArgumentError) Is(err error) bool {
-	var badRequestError *InvalidArgumentError
-	return errors.As(err, &badRequestError)
+func (*InvalidArgumentError) errorCode() *ErrorCode {
+	return InvalidArgument
 }
 
 type MalformedRequestError struct {
@@ -72,12 +76,11 @@ func NewMalformedRequestError(format string, args ...any) *MalformedRequestError
 }
 
 func IsMalformedRequestError(err error) bool {
-	return errors.Is(err, &MalformedRequestError{})
+	return HasCode(err, MalformedRequest)
 }
 
-func (*MalformedRequestError) Is(err error) bool {
-	var malformedRequestError *MalformedRequestError
-	return errors.As(err, &malformedRequestError)
+func (*MalformedRequestError) errorCode() *ErrorCode {
+	return MalformedRequest
 }
 
 type FailedPreconditionError struct {
@@ -89,12 +92,11 @@ func NewFailedPreconditionError(format string, args ...any) *FailedPreconditionE
 }
 
 func IsFailedPreconditionError(err error) bool {
-	return errors.Is(err, &FailedPreconditionError{})
+	return HasCode(err, FailedPrecondition)
 }
 
-func (*FailedPreconditionError) Is(err error) bool {
-	var failedPreconditionError *FailedPreconditionError
-	return errors.As(err, &failedPreconditionError)
+func (*FailedPreconditionError) errorCode() *ErrorCode {
+	return FailedPrecondition
 }
 
 type OutOfRangeError struct {
@@ -106,12 +108,11 @@ func NewOutOfRangeError(format string, args ...any) *OutOfRangeError {
 }
 
 func IsOutOfRangeError(err error) bool {
-	return errors.Is(err, &OutOfRangeError{})
+	return HasCode(err, OutOfRange)
 }
 
-func (*OutOfRangeError) Is(err error) bool {
-	var outOfRangeError *OutOfRangeError
-	return errors.As(err, &outOfRangeError)
+func (*OutOfRangeError) errorCode() *ErrorCode {
+	return OutOfRange
 }
 
 type UnauthenticatedError struct {
@@ -123,12 +124,11 @@ func NewUnauthenticatedError(format string, args ...any) *UnauthenticatedError {
 }
 
 func IsUnauthenticatedError(err error) bool {
-	return errors.Is(err, &UnauthenticatedError{})
+	return HasCode(err, Unauthenticated)
 }
 
-func (*UnauthenticatedError) Is(err error) bool {
-	var unauthenticatedError *UnauthenticatedError
-	return errors.As(err, &unauthenticatedError)
+func (*UnauthenticatedError) errorCode() *ErrorCode {
+	return Unauthenticated
 }
 
 type PermissionDeniedError struct {
@@ -140,12 +140,11 @@ func NewPermissionDeniedError(format string, args ...any) *PermissionDeniedError
 }
 
 func IsPermissionDeniedError(err error) bool {
-	return errors.Is(err, &PermissionDeniedError{})
+	return HasCode(err, PermissionDenied)
 }
 
-func (*PermissionDeniedError) Is(err error) bool {
-	var permissionDeniedError *PermissionDeniedError
-	return errors.As(err, &permissionDeniedError)
+func (*PermissionDeniedError) errorCode() *ErrorCode {
+	return PermissionDenied
 }
 
 type NotFoundError struct {
@@ -157,13 +156,11 @@ func NewNotFoundError(format string, args ...any) *NotFoundError {
 }
 
 func IsNotFoundError(err error) bool {
-	return errors.Is(err, &NotFoundError{})
+	return HasCode(err, NotFound)
 }
 
-func (*NotFoundError) Is(err error) bool {
-	var notFoundError *NotFoundError
-	return errors.As(err, &notFoundError)
-
+func (*NotFoundError) errorCode() *ErrorCode {
+	return NotFound
 }
 
 type AlreadyExistsError struct {
@@ -175,12 +172,11 @@ func NewAlreadyExistsError(format string, args ...any) *AlreadyExistsError {
 }
 
 func IsAlreadyExistsError(err error) bool {
-	return errors.Is(err, &AlreadyExistsError{})
+	return HasCode(err, AlreadyExists)
 }
 
-func (*AlreadyExistsError) Is(err error) bool {
-	var alreadyExistsError *AlreadyExistsError
-	return errors.As(err, &alreadyExistsError)
+func (*AlreadyExistsError) errorCode() *ErrorCode {
+	return AlreadyExists
 }
 
 type AbortedError struct {
@@ -192,12 +188,11 @@ func NewAbortedError(format string, args ...any) *AbortedError {
 }
 
 func IsAbortedError(err error) bool {
-	return errors.Is(err, &AbortedError{})
+	return HasCode(err, Aborted)
 }
 
-func (*AbortedError) Is(err error) bool {
-	var abortedError *AbortedError
-	return errors.As(err, &abortedError)
+func (*AbortedError) errorCode() *ErrorCode {
+	return Aborted
 }
 
 type ResourceExhaustedError struct {
@@ -209,12 +204,11 @@ func NewResourceExhaustedError(format string, args ...any) *ResourceExhaustedErr
 }
 
 func IsResourceExhaustedError(err error) bool {
-	return errors.Is(err, &ResourceExhaustedError{})
+	return HasCode(err, ResourceExhausted)
 }
 
-func (*ResourceExhaustedError) Is(err error) bool {
-	var resourceExhaustedError *ResourceExhaustedError
-	return errors.As(err, &resourceExhaustedError)
+func (*ResourceExhaustedError) errorCode() *ErrorCode {
+	return ResourceExhausted
 }
 
 type CancelledError struct {
@@ -226,12 +220,11 @@ func NewCancelledError(format string, args ...any) *CancelledError {
 }
 
 func IsCancelledError(err error) bool {
-	return errors.Is(err, &CancelledError{})
+	return HasCode(err, Cancelled)
 }
 
-func (*CancelledError) Is(err error) bool {
-	var cancelledError *CancelledError
-	return errors.As(err, &cancelledError)
+func (*CancelledError) errorCode() *ErrorCode {
+	return Cancelled
 }
 
 type UnknownErrorError struct {
@@ -243,12 +236,11 @@ func NewUnknownErrorError(format string, args ...any) *UnknownErrorError {
 }
 
 func IsUnknownErrorError(err error) bool {
-	return errors.Is(err, &UnknownErrorError{})
+	return HasCode(err, UnknownError)
 }
 
-func (*UnknownErrorError) Is(err error) bool {
-	var unknownErrorError *UnknownErrorError
-	return errors.As(err, &unknownErrorError)
+func (*UnknownErrorError) errorCode() *ErrorCode {
+	return UnknownError
 }
 
 type InternalErrorError struct {
@@ -260,12 +252,11 @@ func NewInternalErrorError(format string, args ...any) *InternalErrorError {
 }
 
 func IsInternalError(err error) bool {
-	return errors.Is(err, &InternalErrorError{})
+	return HasCode(err, InternalError)
 }
 
-func (*InternalErrorError) Is(err error) bool {
-	var internalErrorErrorError *InternalErrorError
-	return errors.As(err, &internalErrorErrorError)
+func (*InternalErrorError) errorCode() *ErrorCode {
+	return InternalError
 }
 
 type DataLossError struct {
@@ -277,12 +268,11 @@ func NewDataLossError(format string, args ...any) *DataLossError {
 }
 
 func IsDataLossError(err error) bool {
-	return errors.Is(err, &DataLossError{})
+	return HasCode(err, DataLoss)
 }
 
-func (*DataLossError) Is(err error) bool {
-	var dataLossError *DataLossError
-	return errors.As(err, &dataLossError)
+func (*DataLossError) errorCode() *ErrorCode {
+	return DataLoss
 }
 
 type UnimplementedError struct {
@@ -294,12 +284,11 @@ func NewUnimplementedError(format string, args ...any) *UnimplementedError {
 }
 
 func IsUnimplementedError(err error) bool {
-	return errors.Is(err, &UnimplementedError{})
+	return HasCode(err, Unimplemented)
 }
 
-func (*UnimplementedError) Is(err error) bool {
-	var unimplementedError *UnimplementedError
-	return errors.As(err, &unimplementedError)
+func (*UnimplementedError) errorCode() *ErrorCode {
+	return Unimplemented
 }
 
 type UnavailableError struct {
@@ -311,12 +300,11 @@ func NewUnavailableError(format string, args ...any) *UnavailableError {
 }
 
 func IsUnavailableError(err error) bool {
-	return errors.Is(err, &UnavailableError{})
+	return HasCode(err, Unavailable)
 }
 
-func (*UnavailableError) Is(err error) bool {
-	var unavailableErrorError *UnavailableError
-	return errors.As(err, &unavailableErrorError)
+func (*UnavailableError) errorCode() *ErrorCode {
+	return Unavailable
 }
 
 type DeadlineExceededError struct {
@@ -328,10 +316,9 @@ func NewDeadlineExceededError(format string, args ...any) *DeadlineExceededError
 }
 
 func IsDeadlineExceededError(err error) bool {
-	return errors.Is(err, &DeadlineExceededError{})
+	return HasCode(err, DeadlineExceeded)
 }
 
-func (*DeadlineExceededError) Is(err error) bool {
-	var deadlineExceededError *DeadlineExceededError
-	return errors.As(err, &deadlineExceededError)
+func (*DeadlineExceededError) errorCode() *ErrorCode {
+	return DeadlineExceeded
 }
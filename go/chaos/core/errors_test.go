@@ -1,9 +1,13 @@
 package core
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
+	jsoniter "github.com/json-iterator/go"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestNewNotFoundError(t *testing.T) {
@@ -12,3 +16,54 @@ func TestNewNotFoundError(t *testing.T) {
 	notFoundError := NewNotFoundError(key)
 	assert.Equal(t, notFoundError, NewNotFoundError(key))
 }
+
+func TestErrorIs_Code(t *testing.T) {
+	typed := NewNotFoundError("user %d", 7)
+	assert.True(t, errors.Is(typed, NotFound))
+	assert.True(t, HasCode(typed, NotFound))
+	assert.False(t, HasCode(typed, AlreadyExists))
+	assert.True(t, IsNotFoundError(typed))
+	assert.True(t, errors.Is(typed, &NotFoundError{}))
+	assert.False(t, errors.Is(typed, &AlreadyExistsError{}))
+
+	from := NewErrorFrom(404, "user 7")
+	assert.True(t, errors.Is(from, NotFound))
+	assert.True(t, IsNotFoundError(from))
+	assert.True(t, errors.Is(from, typed))
+	assert.True(t, IsError(from))
+
+	decoded := &Error{}
+	assert.NoError(t, jsoniter.UnmarshalFromString(`{"code":"404","message":"user 7"}`, decoded))
+	assert.True(t, IsNotFoundError(decoded))
+	assert.True(t, errors.Is(fmt.Errorf("load: %w", decoded), NotFound))
+
+	wrapped := Wrap(decoded, InternalError, "load user")
+	assert.True(t, HasCode(wrapped, InternalError))
+	assert.True(t, HasCode(wrapped, NotFound))
+	bs, _ := proto.Marshal(AsError(wrapped))
+	offWire := &Error{}
+	assert.NoError(t, proto.Unmarshal(bs, offWire))
+	assert.True(t, IsNotFoundError(offWire))
+
+	// codes of other domains are resolved by their number
+	quota := &ErrorCode{Domain: "test.is", Code: 600143001, Name: "QUOTA_EXCEEDED", HttpStatusCode: 429}
+	assert.NoError(t, RegisterErrorCode(quota))
+	decoded = &Error{}
+	assert.NoError(t, jsoniter.UnmarshalFromString(`{"code":"600143001","message":"quota"}`, decoded))
+	assert.Same(t, quota, decoded.Code)
+	assert.True(t, HasCode(decoded, quota))
+
+	// decoding into an error of a registered code leaves the code alone
+	reused := NewError(NotFound, "")
+	assert.NoError(t, jsoniter.UnmarshalFromString(`{"code":"600121999"}`, reused))
+	assert.Equal(t, int32(600121999), reused.Code.Code)
+	assert.Equal(t, int32(404), NotFound.Code)
+
+	other := &ErrorCode{Domain: "test.is", Code: 404, Name: "NOT_FOUND"}
+	assert.False(t, HasCode(NewError(other, "x"), NotFound))
+	assert.True(t, HasCode(NewError(other, "x"), other))
+
+	assert.False(t, HasCode(errors.New("x"), NotFound))
+	assert.False(t, HasCode(nil, NotFound))
+	assert.False(t, IsError(errors.New("x")))
+}
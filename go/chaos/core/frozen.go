@@ -0,0 +1,375 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	jsoniter "github.com/json-iterator/go"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrFrozen is returned by the mutators of FrozenObject.
+var ErrFrozen = errors.New("frozen object can not be mutated, derive a new one with With")
+
+// FrozenObject is a deeply immutable snapshot of an Object. It is safe to
+// share between goroutines without locking: it only exposes read accessors
+// and copy-on-write derivations, which share every untouched subtree with
+// the snapshot they were derived from.
+type FrozenObject struct {
+	obj *Object
+}
+
+// FrozenValue is a read-only view of a Value inside a FrozenObject.
+type FrozenValue struct {
+	val *Value
+}
+
+// Freeze returns an immutable snapshot of x. The snapshot is a deep copy, so
+// later mutations of x are not visible through it.
+func (x *Object) Freeze() *FrozenObject {
+	if x == nil {
+		return &FrozenObject{obj: NewObject()}
+	}
+	return &FrozenObject{obj: proto.Clone(x).(*Object)}
+}
+
+// Freeze returns an immutable snapshot of x.
+func (x *Value) Freeze() FrozenValue {
+	if x == nil {
+		return FrozenValue{}
+	}
+	return FrozenValue{val: proto.Clone(x).(*Value)}
+}
+
+func (f *FrozenObject) Len() int {
+	if f == nil {
+		return 0
+	}
+	return len(f.obj.GetVals())
+}
+
+func (f *FrozenObject) Has(key string) bool {
+	if f == nil {
+		return false
+	}
+	_, ok := f.obj.GetVals()[key]
+	return ok
+}
+
+// Keys returns the keys of the snapshot in sorted order.
+func (f *FrozenObject) Keys() []string {
+	if f == nil {
+		return nil
+	}
+	return sortedKeys(f.obj.GetVals())
+}
+
+// Get returns the value at the dotted path, see Value.GetPath.
+func (f *FrozenObject) Get(path string) FrozenValue {
+	if f == nil {
+		return FrozenValue{}
+	}
+	return FrozenValue{val: f.obj.GetPath(path)}
+}
+
+func (f *FrozenObject) GetBool(path string) bool {
+	return f.Get(path).GetBool()
+}
+
+func (f *FrozenObject) GetInt(path string) int {
+	return f.Get(path).GetInt()
+}
+
+func (f *FrozenObject) GetInt64(path string) int64 {
+	return f.Get(path).GetInt64()
+}
+
+func (f *FrozenObject) GetFloat64(path string) float64 {
+	return f.Get(path).GetFloat64()
+}
+
+func (f *FrozenObject) GetString(path string) string {
+	return f.Get(path).GetString()
+}
+
+func (f *FrozenObject) GetObject(path string) *FrozenObject {
+	return f.Get(path).GetObject()
+}
+
+// With returns a new snapshot with val set at the dotted path. Missing
+// objects along the path are created, and only the objects and arrays on the
+// path are copied; all other subtrees are shared with f. It fails if the path
+// runs through a value which is neither an object nor an array.
+func (f *FrozenObject) With(path string, val *Value) (*FrozenObject, error) {
+	segments := splitPath(path)
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("FrozenObject.With: empty path")
+	}
+
+	var root *Object
+	if f != nil {
+		root = f.obj
+	}
+	v, err := withPath(NewObjectValue(root), segments, cloneValue(val))
+	if err != nil {
+		return nil, fmt.Errorf("FrozenObject.With %q: %w", path, err)
+	}
+	return &FrozenObject{obj: v.GetObject()}, nil
+}
+
+// Without returns a new snapshot with the value at the dotted path removed.
+// If nothing is found at path, f itself is returned.
+func (f *FrozenObject) Without(path string) *FrozenObject {
+	if f == nil {
+		return nil
+	}
+	if v, ok := withoutPath(NewObjectValue(f.obj), splitPath(path)); ok {
+		return &FrozenObject{obj: v.GetObject()}
+	}
+	return f
+}
+
+// WithMerged returns a new snapshot with the top-level keys of o set on top
+// of f.
+func (f *FrozenObject) WithMerged(o *Object) *FrozenObject {
+	obj := NewObject()
+	if f != nil {
+		for k, v := range f.obj.GetVals() {
+			obj.Vals[k] = v
+		}
+	}
+	for k, v := range o.GetVals() {
+		obj.Vals[k] = cloneValue(v)
+	}
+	return &FrozenObject{obj: obj}
+}
+
+// SetValue always fails, use With instead.
+func (f *FrozenObject) SetValue(key string, val *Value) error {
+	return ErrFrozen
+}
+
+// Merge always fails, use WithMerged instead.
+func (f *FrozenObject) Merge(o *Object) error {
+	return ErrFrozen
+}
+
+// Delete always fails, use Without instead.
+func (f *FrozenObject) Delete(key string) error {
+	return ErrFrozen
+}
+
+// Thaw returns a mutable deep copy of the snapshot.
+func (f *FrozenObject) Thaw() *Object {
+	if f == nil {
+		return nil
+	}
+	return proto.Clone(f.obj).(*Object)
+}
+
+func (f *FrozenObject) AsMap() map[string]any {
+	if f == nil {
+		return nil
+	}
+	return f.obj.AsMap()
+}
+
+func (f *FrozenObject) MarshalJSON() ([]byte, error) {
+	if f == nil {
+		return []byte("null"), nil
+	}
+	return jsoniter.ConfigFastest.Marshal(f.obj)
+}
+
+func (v FrozenValue) IsValid() bool {
+	return v.val != nil
+}
+
+func (v FrozenValue) GetKind() ValueKind {
+	return v.val.GetKind()
+}
+
+func (v FrozenValue) GetBool() bool {
+	return v.val.GetBool()
+}
+
+func (v FrozenValue) GetInt() int {
+	return v.val.GetInt()
+}
+
+func (v FrozenValue) GetInt64() int64 {
+	return v.val.GetInt64()
+}
+
+func (v FrozenValue) GetUint64() uint64 {
+	return v.val.GetUint64()
+}
+
+func (v FrozenValue) GetFloat64() float64 {
+	return v.val.GetFloat64()
+}
+
+func (v FrozenValue) GetString() string {
+	return v.val.GetString()
+}
+
+// GetBytes returns a copy of the bytes value.
+func (v FrozenValue) GetBytes() []byte {
+	if bs := v.val.GetBytes(); bs != nil {
+		return append([]byte{}, bs...)
+	}
+	return nil
+}
+
+func (v FrozenValue) GetObject() *FrozenObject {
+	if obj := v.val.GetObject(); obj != nil {
+		return &FrozenObject{obj: obj}
+	}
+	return nil
+}
+
+// Get returns the value at the dotted path below v.
+func (v FrozenValue) Get(path string) FrozenValue {
+	return FrozenValue{val: v.val.GetPath(path)}
+}
+
+// Len returns the number of elements of an array or keys of an object.
+func (v FrozenValue) Len() int {
+	if obj := v.val.GetObject(); obj != nil {
+		return len(obj.Vals)
+	}
+	return v.val.arrayLen()
+}
+
+// Index returns the i-th element of an array.
+func (v FrozenValue) Index(i int) FrozenValue {
+	if i >= 0 && i < v.val.arrayLen() {
+		return FrozenValue{val: v.val.arrayIndex(i)}
+	}
+	return FrozenValue{}
+}
+
+func (v FrozenValue) Keys() []string {
+	return sortedKeys(v.val.GetObject().GetVals())
+}
+
+func (v FrozenValue) AsInterface() any {
+	return v.val.AsInterface()
+}
+
+// Thaw returns a mutable deep copy of the value.
+func (v FrozenValue) Thaw() *Value {
+	if v.val == nil {
+		return nil
+	}
+	return proto.Clone(v.val).(*Value)
+}
+
+func (v FrozenValue) MarshalJSON() ([]byte, error) {
+	if v.val == nil {
+		return []byte("null"), nil
+	}
+	return jsoniter.ConfigFastest.Marshal(v.val)
+}
+
+func withPath(v *Value, segments []string, leaf *Value) (*Value, error) {
+	if len(segments) == 0 {
+		return leaf, nil
+	}
+
+	segment := segments[0]
+	if v.GetKind() == ValueKind_VALUE_KIND_ARRAY {
+		vals := v.GetValues()
+		i, ok := pathIndex(segment, len(vals))
+		if !ok {
+			return nil, fmt.Errorf("index %q out of range", segment)
+		}
+		child, err := withPath(vals[i], segments[1:], leaf)
+		if err != nil {
+			return nil, err
+		}
+		copied := make([]*Value, len(vals))
+		copy(copied, vals)
+		copied[i] = child
+		return NewArrayValue(copied...), nil
+	}
+
+	if kind := v.GetKind(); kind != ValueKind_VALUE_KIND_OBJECT && kind != ValueKind_VALUE_KIND_NULL && kind != ValueKind_VALUE_KIND_UNSPECIFIED {
+		return nil, fmt.Errorf("can not set %q of a %s value", segment, kind)
+	}
+	old := v.GetObject().GetVals()
+	child, err := withPath(old[segment], segments[1:], leaf)
+	if err != nil {
+		return nil, err
+	}
+	obj := &Object{Vals: make(map[string]*Value, len(old)+1)}
+	for k, val := range old {
+		obj.Vals[k] = val
+	}
+	obj.Vals[segment] = child
+	return NewObjectValue(obj), nil
+}
+
+func withoutPath(v *Value, segments []string) (*Value, bool) {
+	if len(segments) == 0 {
+		return nil, false
+	}
+
+	segment := segments[0]
+	if v.GetKind() == ValueKind_VALUE_KIND_ARRAY {
+		vals := v.GetValues()
+		i, ok := pathIndex(segment, len(vals))
+		if !ok {
+			return nil, false
+		}
+		copied := make([]*Value, 0, len(vals))
+		copied = append(copied, vals[:i]...)
+		if len(segments) == 1 {
+			copied = append(copied, vals[i+1:]...)
+		} else {
+			child, ok := withoutPath(vals[i], segments[1:])
+			if !ok {
+				return nil, false
+			}
+			copied = append(append(copied, child), vals[i+1:]...)
+		}
+		return NewArrayValue(copied...), true
+	}
+
+	old := v.GetObject().GetVals()
+	val, found := old[segment]
+	if !found {
+		return nil, false
+	}
+	obj := &Object{Vals: make(map[string]*Value, len(old))}
+	for k, val := range old {
+		obj.Vals[k] = val
+	}
+	if len(segments) == 1 {
+		delete(obj.Vals, segment)
+	} else {
+		child, ok := withoutPath(val, segments[1:])
+		if !ok {
+			return nil, false
+		}
+		obj.Vals[segment] = child
+	}
+	return NewObjectValue(obj), true
+}
+
+func cloneValue(v *Value) *Value {
+	if v == nil {
+		return NewNullValue()
+	}
+	return proto.Clone(v).(*Value)
+}
+
+func sortedKeys(m map[string]*Value) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
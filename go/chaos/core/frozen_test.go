@@ -0,0 +1,98 @@
+package core
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObject_Freeze(t *testing.T) {
+	obj := NewObject().
+		SetString("name", "app").
+		SetObject("db", NewObject().SetString("host", "localhost").SetInt("port", 5432)).
+		SetObject("cache", NewObject().SetInt("size", 10)).
+		SetObjectArray("items", NewObject().SetString("id", "a"), NewObject().SetString("id", "b"))
+	frozen := obj.Freeze()
+
+	obj.SetString("name", "changed")
+	obj.GetObject("db").SetInt("port", 1)
+
+	assert.Equal(t, "app", frozen.GetString("name"))
+	assert.Equal(t, 5432, frozen.GetInt("db.port"))
+	assert.Equal(t, "b", frozen.GetString("items.1.id"))
+	assert.Equal(t, []string{"cache", "db", "items", "name"}, frozen.Keys())
+	assert.Equal(t, 2, frozen.Get("items").Len())
+	assert.False(t, frozen.Get("missing.key").IsValid())
+
+	assert.ErrorIs(t, frozen.SetValue("name", NewStringValue("x")), ErrFrozen)
+	assert.ErrorIs(t, frozen.Merge(NewObject()), ErrFrozen)
+	assert.ErrorIs(t, frozen.Delete("name"), ErrFrozen)
+}
+
+func TestFrozenObject_With(t *testing.T) {
+	frozen := NewObject().
+		SetString("name", "app").
+		SetObject("db", NewObject().SetString("host", "localhost").SetInt("port", 5432)).
+		SetObject("cache", NewObject().SetInt("size", 10)).
+		SetObjectArray("items", NewObject().SetString("id", "a"), NewObject().SetString("id", "b")).
+		Freeze()
+
+	derived, err := frozen.With("db.port", NewIntValue(3306))
+	assert.NoError(t, err)
+	assert.Equal(t, 3306, derived.GetInt("db.port"))
+	assert.Equal(t, 5432, frozen.GetInt("db.port"))
+	assert.Same(t, frozen.obj.Vals["cache"], derived.obj.Vals["cache"])
+	assert.NotSame(t, frozen.obj.Vals["db"], derived.obj.Vals["db"])
+
+	derived, err = frozen.With("items.0.id", NewStringValue("z"))
+	assert.NoError(t, err)
+	assert.Equal(t, "z", derived.GetString("items.0.id"))
+	assert.Equal(t, "a", frozen.GetString("items.0.id"))
+	assert.Same(t, frozen.obj.GetPath("items.1"), derived.obj.GetPath("items.1"))
+
+	derived, err = frozen.With("new.nested.key", NewBoolValue(true))
+	assert.NoError(t, err)
+	assert.True(t, derived.GetBool("new.nested.key"))
+
+	_, err = frozen.With("items.5.id", NewStringValue("z"))
+	assert.Error(t, err)
+	_, err = frozen.With("name.first", NewStringValue("z"))
+	assert.Error(t, err)
+
+	removed := frozen.Without("db.host")
+	assert.False(t, removed.Get("db.host").IsValid())
+	assert.Equal(t, "localhost", frozen.GetString("db.host"))
+	assert.Same(t, frozen, frozen.Without("db.missing"))
+
+	merged := frozen.WithMerged(NewObject().SetString("name", "merged"))
+	assert.Equal(t, "merged", merged.GetString("name"))
+	assert.Equal(t, "app", frozen.GetString("name"))
+}
+
+func TestFrozenObject_Concurrent(t *testing.T) {
+	frozen := NewObject().
+		SetString("name", "app").
+		SetObject("db", NewObject().SetString("host", "localhost").SetInt("port", 5432)).
+		SetObject("cache", NewObject().SetInt("size", 10)).
+		SetObjectArray("items", NewObject().SetString("id", "a"), NewObject().SetString("id", "b")).
+		Freeze()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			derived, err := frozen.With("cache.size", NewIntValue(i))
+			assert.NoError(t, err)
+			assert.Equal(t, i, derived.GetInt("cache.size"))
+			assert.Equal(t, "localhost", frozen.GetString("db.host"))
+			_, _ = derived.MarshalJSON()
+		}(i)
+	}
+	wg.Wait()
+
+	thawed := frozen.Thaw()
+	thawed.SetString("name", "thawed")
+	assert.Equal(t, "app", frozen.GetString("name"))
+}
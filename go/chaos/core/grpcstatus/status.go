@@ -0,0 +1,27 @@
+// Package grpcstatus converts the statuses gRPC clients receive into
+// core.Errors. Servers need no conversion, as core.Error has GRPCStatus.
+package grpcstatus
+
+import (
+	"google.golang.org/grpc/status"
+
+	"github.com/chaos-io/core/go/chaos/core"
+)
+
+// FromStatus converts s into a core.Error, nil for OK, see core.FromStatus.
+func FromStatus(s *status.Status) (*core.Error, error) {
+	p := s.Proto()
+	return core.FromStatus(&core.Status{Code: p.GetCode(), Message: p.GetMessage(), Details: p.GetDetails()})
+}
+
+// FromError converts the error a gRPC client returns into a core.Error, nil
+// for a nil err. Errors without status are UnknownError.
+func FromError(err error) (*core.Error, error) {
+	if err == nil {
+		return nil, nil
+	}
+	if e := core.AsError(err); e != nil {
+		return e, nil
+	}
+	return FromStatus(status.Convert(err))
+}
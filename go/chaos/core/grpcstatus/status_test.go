@@ -0,0 +1,39 @@
+package grpcstatus
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/chaos-io/core/go/chaos/core"
+)
+
+func TestFromStatus(t *testing.T) {
+	sent := core.AsError(core.Wrap(errors.New("connection reset"), core.Unavailable, "load user")).
+		AddDetail(map[string]any{"retry": 3})
+	s := sent.GRPCStatus()
+	assert.Equal(t, codes.Unavailable, s.Code())
+
+	received, err := FromError(s.Err())
+	assert.NoError(t, err)
+	assert.Same(t, core.Unavailable, received.Code)
+	assert.Equal(t, "load user", received.Message)
+	assert.Equal(t, int64(3), received.Details[0].GetObject().GetInt64("retry"))
+	assert.Equal(t, "connection reset", received.Cause.Message)
+
+	plain, err := FromError(status.Error(codes.PermissionDenied, "denied"))
+	assert.NoError(t, err)
+	assert.Same(t, core.PermissionDenied, plain.Code)
+
+	unknown, err := FromError(errors.New("boom"))
+	assert.NoError(t, err)
+	assert.Same(t, core.UnknownError, unknown.Code)
+
+	ok, err := FromError(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, ok)
+	assert.Equal(t, codes.OK, (*core.Error)(nil).GRPCStatus().Code())
+}
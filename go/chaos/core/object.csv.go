@@ -0,0 +1,553 @@
+package core
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// CSVType is the type a CSV cell is parsed into.
+type CSVType int
+
+const (
+	// CSVAuto infers the type of every cell: integers, floats, true/false,
+	// bytes in the form of CSVBytes and null markers are parsed, anything else
+	// is kept as a string.
+	CSVAuto CSVType = iota
+	CSVString
+	CSVInt
+	CSVFloat
+	CSVBool
+	// CSVTimestamp parses any layout of Timestamp.Parse and stores the
+	// Timestamp in its JSON form.
+	CSVTimestamp
+	// CSVJSON holds arrays and other values in their JSON encoding.
+	CSVJSON
+	// CSVBytes holds BYTES values in the base64 form of BytesJSONPrefix,
+	// e.g. "b64.AQID".
+	CSVBytes
+)
+
+func (t CSVType) String() string {
+	switch t {
+	case CSVString:
+		return "string"
+	case CSVInt:
+		return "int"
+	case CSVFloat:
+		return "float"
+	case CSVBool:
+		return "bool"
+	case CSVTimestamp:
+		return "timestamp"
+	case CSVJSON:
+		return "json"
+	case CSVBytes:
+		return "bytes"
+	default:
+		return "auto"
+	}
+}
+
+// CSVColumn maps a CSV column to a dotted Object path, see Value.GetPath.
+type CSVColumn struct {
+	Name string
+	// Path defaults to Name.
+	Path string
+	Type CSVType
+}
+
+func (c *CSVColumn) path() string {
+	if len(c.Path) > 0 {
+		return c.Path
+	}
+	return c.Name
+}
+
+// CSVSchema describes the columns of a CSV table of Objects.
+type CSVSchema struct {
+	Columns []*CSVColumn
+	// NullMarkers are the cells read as null, the first one is written for
+	// null and missing values. Empty markers default to "".
+	NullMarkers []string
+}
+
+func (s *CSVSchema) nullMarker() string {
+	if s != nil && len(s.NullMarkers) > 0 {
+		return s.NullMarkers[0]
+	}
+	return ""
+}
+
+func (s *CSVSchema) isNull(cell string) bool {
+	if s == nil || len(s.NullMarkers) == 0 {
+		return len(cell) == 0
+	}
+	for _, marker := range s.NullMarkers {
+		if cell == marker {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *CSVSchema) column(name string) *CSVColumn {
+	if s != nil {
+		for _, c := range s.Columns {
+			if c.Name == name {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// InferCSVSchema returns the columns of the flattened leaf paths of objects,
+// in sorted path order. Nested objects are flattened into dotted paths, and
+// arrays are kept as JSON cells.
+func InferCSVSchema(objects ...*Object) *CSVSchema {
+	types := make(map[string]CSVType)
+	var paths []string
+	for _, obj := range objects {
+		Walk(NewObjectValue(obj), func(path string, val *Value) WalkAction {
+			if len(path) == 0 {
+				return WalkContinue
+			}
+			var typ CSVType
+			switch val.GetKind() {
+			case ValueKind_VALUE_KIND_OBJECT:
+				if len(val.GetObject().GetVals()) > 0 {
+					return WalkContinue
+				}
+				typ = CSVJSON
+			case ValueKind_VALUE_KIND_ARRAY:
+				typ = CSVJSON
+			case ValueKind_VALUE_KIND_NULL, ValueKind_VALUE_KIND_UNSPECIFIED:
+				typ = CSVAuto
+			case ValueKind_VALUE_KIND_BOOLEAN:
+				typ = CSVBool
+			case ValueKind_VALUE_KIND_INTEGER:
+				typ = CSVInt
+			case ValueKind_VALUE_KIND_NUMBER:
+				typ = CSVFloat
+			case ValueKind_VALUE_KIND_BYTES:
+				typ = CSVBytes
+			default:
+				typ = CSVString
+			}
+
+			old, found := types[path]
+			switch {
+			case !found:
+				paths = append(paths, path)
+				types[path] = typ
+			case old == CSVAuto:
+				types[path] = typ
+			case typ == CSVAuto || old == typ:
+			case old == CSVInt && typ == CSVFloat || old == CSVFloat && typ == CSVInt:
+				types[path] = CSVFloat
+			default:
+				types[path] = CSVString
+			}
+			return WalkSkip
+		})
+	}
+
+	schema := &CSVSchema{}
+	sort.Strings(paths)
+	for _, p := range paths {
+		schema.Columns = append(schema.Columns, &CSVColumn{Name: p, Path: p, Type: types[p]})
+	}
+	return schema
+}
+
+// CSVRowError reports a row which could not be read or written. Row is the
+// 1-based line of the row in the CSV text, the header being line 1.
+type CSVRowError struct {
+	Row    int
+	Column string
+	Err    error
+}
+
+func (e *CSVRowError) Error() string {
+	if len(e.Column) > 0 {
+		return fmt.Sprintf("csv row %d, column %q: %v", e.Row, e.Column, e.Err)
+	}
+	return fmt.Sprintf("csv row %d: %v", e.Row, e.Err)
+}
+
+func (e *CSVRowError) Unwrap() error {
+	return e.Err
+}
+
+// CSVReader streams Objects from CSV text. The first record is the header;
+// its columns are looked up by name in the schema, and columns missing from
+// the schema are read as CSVAuto at the path of their name.
+type CSVReader struct {
+	reader  *csv.Reader
+	schema  *CSVSchema
+	columns []*CSVColumn
+	row     int
+}
+
+// NewCSVReader returns a reader of r, the schema may be nil.
+func NewCSVReader(r io.Reader, schema *CSVSchema) *CSVReader {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+	reader.FieldsPerRecord = -1
+	return &CSVReader{reader: reader, schema: schema}
+}
+
+// NewTSVReader returns a reader of tab separated values.
+func NewTSVReader(r io.Reader, schema *CSVSchema) *CSVReader {
+	return NewCSVReader(r, schema).WithComma('\t')
+}
+
+func (r *CSVReader) WithComma(comma rune) *CSVReader {
+	r.reader.Comma = comma
+	if comma == '\t' {
+		r.reader.LazyQuotes = true
+	}
+	return r
+}
+
+// Columns returns the columns of the header, nil before the first Read.
+func (r *CSVReader) Columns() []*CSVColumn {
+	return r.columns
+}
+
+// Read returns the next Object, or io.EOF at the end of the input. A row
+// which fails to parse is reported as a *CSVRowError, and reading can go on
+// with the next row.
+func (r *CSVReader) Read() (*Object, error) {
+	if r.columns == nil {
+		if err := r.readHeader(); err != nil {
+			return nil, err
+		}
+	}
+
+	record, err := r.reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, &CSVRowError{Row: parseErr.StartLine, Err: parseErr.Err}
+		}
+		return nil, err
+	}
+	r.row, _ = r.reader.FieldPos(0)
+
+	obj := NewObject()
+	for i, cell := range record {
+		if i >= len(r.columns) {
+			return nil, &CSVRowError{Row: r.row, Err: fmt.Errorf("%d cells for %d columns", len(record), len(r.columns))}
+		}
+		column := r.columns[i]
+		val, err := parseCSVCell(r.schema, column.Type, cell)
+		if err != nil {
+			return nil, &CSVRowError{Row: r.row, Column: column.Name, Err: err}
+		}
+		if err = setCSVPath(obj, column.path(), val); err != nil {
+			return nil, &CSVRowError{Row: r.row, Column: column.Name, Err: err}
+		}
+	}
+	return obj, nil
+}
+
+func (r *CSVReader) readHeader() error {
+	header, err := r.reader.Read()
+	if err != nil {
+		return err
+	}
+	r.columns = make([]*CSVColumn, 0, len(header))
+	for _, name := range header {
+		column := r.schema.column(name)
+		if column == nil {
+			column = &CSVColumn{Name: name, Path: name}
+		}
+		r.columns = append(r.columns, column)
+	}
+	return nil
+}
+
+// ReadCSV reads all rows of r. Rows which fail to parse are skipped, and
+// their *CSVRowError are joined into the returned error.
+func ReadCSV(r io.Reader, schema *CSVSchema) ([]*Object, error) {
+	return readAllCSV(NewCSVReader(r, schema))
+}
+
+// ReadTSV reads all rows of tab separated values, see ReadCSV.
+func ReadTSV(r io.Reader, schema *CSVSchema) ([]*Object, error) {
+	return readAllCSV(NewTSVReader(r, schema))
+}
+
+func readAllCSV(reader *CSVReader) ([]*Object, error) {
+	var objects []*Object
+	var errs []error
+	for {
+		obj, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var rowErr *CSVRowError
+		if errors.As(err, &rowErr) {
+			errs = append(errs, err)
+			continue
+		}
+		if err != nil {
+			return objects, err
+		}
+		objects = append(objects, obj)
+	}
+	return objects, errors.Join(errs...)
+}
+
+// CSVWriter streams Objects as CSV text. The header is written with the
+// first Object, in the order of the schema columns. A nil schema is inferred
+// from the first Object, see InferCSVSchema.
+type CSVWriter struct {
+	writer        *csv.Writer
+	schema        *CSVSchema
+	headerWritten bool
+	row           int
+}
+
+// NewCSVWriter returns a writer to w with the columns of schema, the schema
+// may be nil.
+func NewCSVWriter(w io.Writer, schema *CSVSchema) *CSVWriter {
+	return &CSVWriter{writer: csv.NewWriter(w), schema: schema}
+}
+
+// NewTSVWriter returns a writer of tab separated values.
+func NewTSVWriter(w io.Writer, schema *CSVSchema) *CSVWriter {
+	return NewCSVWriter(w, schema).WithComma('\t')
+}
+
+func (w *CSVWriter) WithComma(comma rune) *CSVWriter {
+	w.writer.Comma = comma
+	return w
+}
+
+// WriteHeader writes the header unless it is written already. Without a
+// schema there is no header to write yet, so it fails; Write infers the
+// schema and writes the header then.
+func (w *CSVWriter) WriteHeader() error {
+	if w.headerWritten {
+		return nil
+	}
+	if w.schema == nil {
+		return errors.New("csv header of a writer without schema")
+	}
+	header := make([]string, 0, len(w.schema.Columns))
+	for _, c := range w.schema.Columns {
+		header = append(header, c.Name)
+	}
+	w.headerWritten = true
+	w.row = 1
+	return w.writer.Write(header)
+}
+
+// Write writes obj as the next row. Values which do not fit the type of
+// their column fail with a *CSVRowError.
+func (w *CSVWriter) Write(obj *Object) error {
+	if w.schema == nil {
+		w.schema = InferCSVSchema(obj)
+	}
+	if err := w.WriteHeader(); err != nil {
+		return err
+	}
+	w.row++
+
+	record := make([]string, 0, len(w.schema.Columns))
+	for _, c := range w.schema.Columns {
+		cell, err := formatCSVCell(w.schema, c.Type, obj.GetPath(c.path()))
+		if err != nil {
+			return &CSVRowError{Row: w.row, Column: c.Name, Err: err}
+		}
+		record = append(record, cell)
+	}
+	return w.writer.Write(record)
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (w *CSVWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// WriteCSV writes objects as CSV, a nil schema is inferred from objects.
+func WriteCSV(w io.Writer, objects []*Object, schema *CSVSchema) error {
+	if schema == nil {
+		schema = InferCSVSchema(objects...)
+	}
+	return writeAllCSV(NewCSVWriter(w, schema), objects)
+}
+
+// WriteTSV writes objects as tab separated values, see WriteCSV.
+func WriteTSV(w io.Writer, objects []*Object, schema *CSVSchema) error {
+	if schema == nil {
+		schema = InferCSVSchema(objects...)
+	}
+	return writeAllCSV(NewTSVWriter(w, schema), objects)
+}
+
+func writeAllCSV(writer *CSVWriter, objects []*Object) error {
+	if err := writer.WriteHeader(); err != nil {
+		return err
+	}
+	for _, obj := range objects {
+		if err := writer.Write(obj); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+func parseCSVCell(schema *CSVSchema, typ CSVType, cell string) (*Value, error) {
+	if schema.isNull(cell) && (typ != CSVString || len(cell) > 0) {
+		return NewNullValue(), nil
+	}
+
+	switch typ {
+	case CSVString:
+		return NewStringValue(cell), nil
+	case CSVInt:
+		i, err := strconv.ParseInt(strings.TrimSpace(cell), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid int %q", cell)
+		}
+		return NewInt64Value(i), nil
+	case CSVFloat:
+		f, err := strconv.ParseFloat(strings.TrimSpace(cell), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid float %q", cell)
+		}
+		return NewFloat64Value(f), nil
+	case CSVBool:
+		b, err := strconv.ParseBool(strings.TrimSpace(cell))
+		if err != nil {
+			return nil, fmt.Errorf("invalid bool %q", cell)
+		}
+		return NewBoolValue(b), nil
+	case CSVTimestamp:
+		ts, err := ParseTimestamp(strings.TrimSpace(cell))
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp %q", cell)
+		}
+		return NewStringValue(ts.Format()), nil
+	case CSVJSON:
+		val := &Value{}
+		if err := jsoniter.ConfigFastest.UnmarshalFromString(cell, val); err != nil {
+			return nil, fmt.Errorf("invalid json %q: %w", cell, err)
+		}
+		return val, nil
+	case CSVBytes:
+		bs, ok, err := decodeBytesString(strings.TrimSpace(cell))
+		if !ok || err != nil {
+			return nil, fmt.Errorf("invalid bytes %q", cell)
+		}
+		return NewBytesValue(bs), nil
+	default:
+		if bs, ok, err := decodeBytesString(cell); ok && err == nil {
+			return NewBytesValue(bs), nil
+		}
+		return inferScalarValue(cell), nil
+	}
+}
+
+// inferScalarValue parses s as an integer, a finite float or true/false, and
+// keeps it as a string otherwise.
+func inferScalarValue(s string) *Value {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return NewInt64Value(i)
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		return NewFloat64Value(f)
+	}
+	if s == "true" || s == "false" {
+		return NewBoolValue(s == "true")
+	}
+	return NewStringValue(s)
+}
+
+func formatCSVCell(schema *CSVSchema, typ CSVType, val *Value) (string, error) {
+	kind := val.GetKind()
+	if kind == ValueKind_VALUE_KIND_NULL || kind == ValueKind_VALUE_KIND_UNSPECIFIED {
+		return schema.nullMarker(), nil
+	}
+
+	switch typ {
+	case CSVInt:
+		i, lossless, err := CoerceJSONLenient.ToInt64(val)
+		if err != nil || !lossless {
+			return "", fmt.Errorf("%s value is not an int", kind)
+		}
+		return strconv.FormatInt(i, 10), nil
+	case CSVFloat:
+		f, _, err := CoerceJSONLenient.ToFloat64(val)
+		if err != nil {
+			return "", fmt.Errorf("%s value is not a float", kind)
+		}
+		return strconv.FormatFloat(f, 'g', -1, 64), nil
+	case CSVBool:
+		b, _, err := CoerceJSONLenient.ToBool(val)
+		if err != nil {
+			return "", fmt.Errorf("%s value is not a bool", kind)
+		}
+		return strconv.FormatBool(b), nil
+	case CSVTimestamp:
+		ts, _, err := CoerceFormLenient.ToTimestamp(val)
+		if err != nil {
+			return "", fmt.Errorf("%s value is not a timestamp", kind)
+		}
+		return ts.Format(), nil
+	case CSVJSON:
+		return jsoniter.ConfigFastest.MarshalToString(val)
+	case CSVBytes:
+		if kind != ValueKind_VALUE_KIND_BYTES {
+			return "", fmt.Errorf("%s value is not bytes", kind)
+		}
+	}
+
+	switch kind {
+	case ValueKind_VALUE_KIND_OBJECT, ValueKind_VALUE_KIND_ARRAY:
+		return jsoniter.ConfigFastest.MarshalToString(val)
+	case ValueKind_VALUE_KIND_BYTES:
+		// like BytesJSONPrefix, so that no binary data ends up in the CSV
+		return Base64Prefix + base64.StdEncoding.EncodeToString(val.GetBytes()), nil
+	}
+	s, _, err := CoerceJSONLenient.ToString(val)
+	return s, err
+}
+
+func setCSVPath(obj *Object, path string, val *Value) error {
+	segments := splitPath(path)
+	if len(segments) == 0 {
+		return errors.New("empty column path")
+	}
+	for i, segment := range segments[:len(segments)-1] {
+		child := obj.GetValue(segment)
+		switch {
+		case child == nil || child.GetKind() == ValueKind_VALUE_KIND_NULL:
+			next := NewObject()
+			obj.SetObject(segment, next)
+			obj = next
+		case child.GetObject() != nil:
+			obj = child.GetObject()
+		default:
+			return fmt.Errorf("path %q is not an object", strings.Join(segments[:i+1], PathSeparator))
+		}
+	}
+	obj.SetValue(segments[len(segments)-1], val)
+	return nil
+}
@@ -0,0 +1,185 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInferCSVSchema(t *testing.T) {
+	objects := []*Object{
+		NewObject().SetString("name", "alice").SetInt("age", 30).SetBool("admin", true).
+			SetObject("address", NewObject().SetString("city", "Beijing")).
+			SetStringArray("tags", "a", "b"),
+		NewObject().SetString("name", "bob, jr.").SetFloat64("age", 41.5).
+			SetValue("admin", NewNullValue()),
+	}
+
+	schema := InferCSVSchema(objects...)
+	var names []string
+	types := map[string]CSVType{}
+	for _, c := range schema.Columns {
+		names = append(names, c.Name)
+		types[c.Name] = c.Type
+	}
+	assert.Equal(t, []string{"address.city", "admin", "age", "name", "tags"}, names)
+	assert.Equal(t, map[string]CSVType{
+		"address.city": CSVString,
+		"admin":        CSVBool,
+		"age":          CSVFloat,
+		"name":         CSVString,
+		"tags":         CSVJSON,
+	}, types)
+}
+
+func TestWriteCSV(t *testing.T) {
+	objects := []*Object{
+		NewObject().SetString("name", "alice").SetInt("age", 30).SetBool("admin", true).
+			SetObject("address", NewObject().SetString("city", "Beijing")).
+			SetStringArray("tags", "a", "b"),
+		NewObject().SetString("name", "bob, jr.").SetFloat64("age", 41.5).
+			SetValue("admin", NewNullValue()),
+	}
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, WriteCSV(buf, objects, nil))
+	assert.Equal(t, "address.city,admin,age,name,tags\n"+
+		`Beijing,true,30,alice,"[""a"",""b""]"`+"\n"+
+		`,,41.5,"bob, jr.",`+"\n", buf.String())
+
+	buf.Reset()
+	schema := &CSVSchema{
+		Columns:     []*CSVColumn{{Name: "Name", Path: "name"}, {Name: "Age", Path: "age", Type: CSVInt}},
+		NullMarkers: []string{"NULL"},
+	}
+	writer := NewTSVWriter(buf, schema)
+	assert.NoError(t, writer.Write(objects[0]))
+	assert.NoError(t, writer.Write(NewObject().SetString("name", "carol")))
+	err := writer.Write(objects[1])
+	var rowErr *CSVRowError
+	assert.True(t, errors.As(err, &rowErr))
+	assert.Equal(t, 4, rowErr.Row)
+	assert.Equal(t, "Age", rowErr.Column)
+	assert.NoError(t, writer.Flush())
+	assert.Equal(t, "Name\tAge\nalice\t30\ncarol\tNULL\n", buf.String())
+}
+
+func TestCSVWriter_NilSchema(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer := NewCSVWriter(buf, nil)
+	assert.Error(t, writer.WriteHeader())
+	assert.NoError(t, writer.Write(NewObject().SetString("name", "alice").SetInt("age", 30)))
+	assert.NoError(t, writer.Write(NewObject().SetString("name", "bob")))
+	assert.NoError(t, writer.Flush())
+	assert.Equal(t, "age,name\n30,alice\n,bob\n", buf.String())
+}
+
+func TestReadCSV(t *testing.T) {
+	text := "name,age,admin,address.city,tags\n" +
+		"alice,30,true,Beijing,\"[\"\"a\"\"]\"\n" +
+		"bob,41.5,,,\n"
+	objects, err := ReadCSV(strings.NewReader(text), &CSVSchema{Columns: []*CSVColumn{{Name: "tags", Type: CSVJSON}}})
+	assert.NoError(t, err)
+	assert.Len(t, objects, 2)
+	assert.Equal(t, "alice", objects[0].GetString("name"))
+	assert.Equal(t, ValueKind_VALUE_KIND_INTEGER, objects[0].GetValue("age").GetKind())
+	assert.Equal(t, int64(30), objects[0].GetInt64("age"))
+	assert.True(t, objects[0].GetBool("admin"))
+	assert.Equal(t, "Beijing", objects[0].GetPath("address.city").GetString())
+	assert.Equal(t, []string{"a"}, objects[0].GetValue("tags").GetStringArray())
+	assert.Equal(t, 41.5, objects[1].GetFloat64("age"))
+	assert.Equal(t, ValueKind_VALUE_KIND_NULL, objects[1].GetValue("admin").GetKind())
+}
+
+func TestReadCSV_RoundTrip(t *testing.T) {
+	objects := []*Object{
+		NewObject().SetString("name", "alice").SetInt("age", 30).SetBool("admin", true).
+			SetObject("address", NewObject().SetString("city", "Beijing")).
+			SetStringArray("tags", "a", "b"),
+		NewObject().SetString("name", "bob, jr.").SetFloat64("age", 41.5).
+			SetValue("admin", NewNullValue()),
+	}
+
+	buf := &bytes.Buffer{}
+	schema := InferCSVSchema(objects...)
+	assert.NoError(t, WriteTSV(buf, objects, schema))
+
+	read, err := ReadTSV(buf, schema)
+	assert.NoError(t, err)
+	assert.Len(t, read, 2)
+	assert.Equal(t, "bob, jr.", read[1].GetString("name"))
+	assert.Equal(t, 30.0, read[0].GetFloat64("age"))
+	assert.Equal(t, []string{"a", "b"}, read[0].GetValue("tags").GetStringArray())
+}
+
+func TestCSV_Bytes(t *testing.T) {
+	objects := []*Object{
+		NewObject().SetString("name", "a").SetBytes("data", []byte{0xff, 0x00, '\n'}),
+		NewObject().SetString("name", "b").SetValue("data", NewNullValue()),
+	}
+	schema := InferCSVSchema(objects...)
+	assert.Equal(t, CSVBytes, schema.Columns[0].Type)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, WriteCSV(buf, objects, schema))
+	assert.Equal(t, "data,name\nb64./wAK,a\n,b\n", buf.String())
+
+	for _, s := range []*CSVSchema{schema, nil} {
+		read, err := ReadCSV(strings.NewReader(buf.String()), s)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0xff, 0x00, '\n'}, read[0].GetValue("data").GetBytes())
+		assert.Equal(t, ValueKind_VALUE_KIND_NULL, read[1].GetValue("data").GetKind())
+	}
+
+	_, err := ReadCSV(strings.NewReader("data\nAQID\n"), &CSVSchema{Columns: []*CSVColumn{{Name: "data", Type: CSVBytes}}})
+	assert.EqualError(t, err, `csv row 2, column "data": invalid bytes "AQID"`)
+}
+
+func TestCSVReader_RowErrors(t *testing.T) {
+	schema := &CSVSchema{
+		Columns: []*CSVColumn{
+			{Name: "id", Type: CSVInt},
+			{Name: "at", Type: CSVTimestamp},
+			{Name: "ok", Type: CSVBool},
+		},
+		NullMarkers: []string{"", "-"},
+	}
+	text := "id,at,ok\n" +
+		"1,2024-05-01T08:00:00Z,true\n" +
+		"x,2024-05-01T08:00:00Z,false\n" +
+		"3,-,maybe\n" +
+		"4,-,-\n"
+
+	reader := NewCSVReader(strings.NewReader(text), schema)
+	obj, err := reader.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), obj.GetInt64("id"))
+	assert.Equal(t, int64(1714550400), mustParseTimestamp(t, obj.GetString("at")).Seconds)
+
+	_, err = reader.Read()
+	assert.EqualError(t, err, `csv row 3, column "id": invalid int "x"`)
+	_, err = reader.Read()
+	assert.EqualError(t, err, `csv row 4, column "ok": invalid bool "maybe"`)
+
+	obj, err = reader.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, ValueKind_VALUE_KIND_NULL, obj.GetValue("at").GetKind())
+	_, err = reader.Read()
+	assert.Equal(t, io.EOF, err)
+
+	objects, err := ReadCSV(strings.NewReader(text), schema)
+	assert.Len(t, objects, 2)
+	var rowErr *CSVRowError
+	assert.True(t, errors.As(err, &rowErr))
+	assert.Equal(t, 3, rowErr.Row)
+}
+
+func mustParseTimestamp(t *testing.T, s string) *Timestamp {
+	ts, err := ParseTimestamp(s)
+	assert.NoError(t, err)
+	return ts
+}
@@ -104,7 +104,7 @@ func (x *Object) To(val any) error {
 		return err
 	}
 
-	return jsoniter.ConfigFastest.Unmarshal(marshal, &val)
+	return objectToAPI.Unmarshal(marshal, &val)
 }
 
 // From converts a struct into Object.
@@ -466,6 +466,9 @@ func (x *Object) Merge(o *Object) *Object {
 	return x
 }
 
+// Clone returns a shallow copy of x, the values are shared with x, so it does
+// not protect them from mutation. To share an Object between goroutines, use
+// Freeze and derive changes with With, like ConfigLoader.Object does.
 func (x *Object) Clone() *Object {
 	if x != nil {
 		obj := NewObject()
@@ -484,18 +487,21 @@ func (x *Object) Delete(key string) *Object {
 	return x
 }
 
+// ToLowerCamelKeys returns a copy of x with the keys of all nested objects,
+// including objects inside arrays, converted to lower camel case.
 func (x *Object) ToLowerCamelKeys() *Object {
-	obj := NewObject()
-	for k, v := range x.GetVals() {
-		obj.SetValue(strcase.ToLowerCamel(k), v)
-	}
-	return obj
+	return x.transformKeys(strcase.ToLowerCamel)
 }
 
+// ToSnakeKeys returns a copy of x with the keys of all nested objects,
+// including objects inside arrays, converted to snake case.
 func (x *Object) ToSnakeKeys() *Object {
-	obj := NewObject()
-	for k, v := range x.GetVals() {
-		obj.SetValue(strcase.ToSnake(k), v)
+	return x.transformKeys(strcase.ToSnake)
+}
+
+func (x *Object) transformKeys(fn func(string) string) *Object {
+	if obj := TransformKeys(NewObjectValue(x), fn).GetObject(); obj != nil {
+		return obj
 	}
-	return obj
+	return NewObject()
 }
@@ -43,10 +43,15 @@ func (codec *ObjectCodec) IsEmpty(ptr unsafe.Pointer) bool {
 }
 
 func (codec *ObjectCodec) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
-	obj := (*Object)(ptr)
-	if obj == nil || obj.Vals == nil {
-		stream.WriteNil()
-		return
-	}
-	stream.WriteVal(obj.Vals)
+	writeObject(stream, (*Object)(ptr).GetVals())
+}
+
+// MarshalJSON encodes x with ObjectCodec, so "encoding/json" produces the same
+// JSON as jsoniter.
+func (x *Object) MarshalJSON() ([]byte, error) {
+	return jsoniter.Marshal(x)
+}
+
+func (x *Object) UnmarshalJSON(data []byte) error {
+	return jsoniter.Unmarshal(data, x)
 }
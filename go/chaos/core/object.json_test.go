@@ -39,3 +39,37 @@ func TestObjectCodec_IsEmpty(t *testing.T) {
 	err := jsoniter.ConfigFastest.UnmarshalFromString(Val, object)
 	assert.NoError(t, err)
 }
+
+func BenchmarkObjectCodec_Encode(b *testing.B) {
+	items := make([]*Value, 0, 100)
+	for i := 0; i < 100; i++ {
+		items = append(items, NewObjectValue(NewObject().SetInt("id", i).SetString("name", "item").SetFloat64("price", 9.5).
+			SetBool("active", i%2 == 0).SetBytes("digest", []byte{1, 2, 3, 4}).SetStringArray("tags", "a", "b")))
+	}
+	obj := NewObject().SetString("kind", "list").SetValue("items", NewArrayValue(items...))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := jsoniter.ConfigFastest.Marshal(obj); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkObject_EncodeAsMap is the generic path through intermediate Go
+// values, for comparison.
+func BenchmarkObject_EncodeAsMap(b *testing.B) {
+	items := make([]*Value, 0, 100)
+	for i := 0; i < 100; i++ {
+		items = append(items, NewObjectValue(NewObject().SetInt("id", i).SetString("name", "item").SetFloat64("price", 9.5).
+			SetBool("active", i%2 == 0).SetBytes("digest", []byte{1, 2, 3, 4}).SetStringArray("tags", "a", "b")))
+	}
+	obj := NewObject().SetString("kind", "list").SetValue("items", NewArrayValue(items...))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := jsoniter.ConfigFastest.Marshal(obj.AsMap()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
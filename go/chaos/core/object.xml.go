@@ -0,0 +1,377 @@
+package core
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// XML is mapped to Objects with the conventions below, the prefix of the
+// attributes and the key of the text are configurable:
+//
+//	<order id="7">                   {
+//	  <item>a</item>                   "@id": "7",
+//	  <item>b</item>                   "item": ["a", "b"],
+//	  <note lang="en">hi</note>        "note": {"@lang": "en", "#text": "hi"},
+//	  <empty/>                         "empty": null
+//	</order>                         }
+//
+//   - an element is the key of its local name in the Object of its parent,
+//     namespaces are dropped.
+//   - attributes are keys of the element prefixed by "@", xmlns declarations
+//     are skipped.
+//   - the text of an element is trimmed. An element with text only is the
+//     text itself, an element with attributes or children holds its text
+//     under "#text", and an element with neither is null.
+//   - repeated elements are an array in document order. Elements which may
+//     occur once are only arrays if declared by WithArrayElements.
+//   - texts are strings, with type inference integers, floats and true/false
+//     are parsed like CSVAuto does.
+//
+// The root element is the Object decoded or encoded, its name is not part of
+// the Object. Encoding is the reverse: keys are written sorted, bytes as
+// base64 and arrays as repeated elements, while arrays of arrays and
+// attributes which are not scalars can not be encoded. Empty arrays and
+// empty strings do not survive a round trip, as they are written as nothing
+// and as empty elements.
+
+const (
+	DefaultXMLAttrPrefix = "@"
+	DefaultXMLTextKey    = "#text"
+	DefaultXMLRoot       = "root"
+)
+
+// XMLDecoder reads Objects from a stream of XML documents.
+type XMLDecoder struct {
+	decoder    *xml.Decoder
+	attrPrefix string
+	textKey    string
+	inferTypes bool
+	arrays     map[string]bool
+	root       string
+}
+
+func NewXMLDecoder(r io.Reader) *XMLDecoder {
+	return &XMLDecoder{
+		decoder:    xml.NewDecoder(r),
+		attrPrefix: DefaultXMLAttrPrefix,
+		textKey:    DefaultXMLTextKey,
+	}
+}
+
+func (d *XMLDecoder) WithAttrPrefix(prefix string) *XMLDecoder {
+	d.attrPrefix = prefix
+	return d
+}
+
+func (d *XMLDecoder) WithTextKey(key string) *XMLDecoder {
+	d.textKey = key
+	return d
+}
+
+// WithInferTypes parses integers, floats and booleans in texts and
+// attributes, which are strings otherwise.
+func (d *XMLDecoder) WithInferTypes(infer bool) *XMLDecoder {
+	d.inferTypes = infer
+	return d
+}
+
+// WithArrayElements declares the elements which are always arrays, even when
+// they occur once.
+func (d *XMLDecoder) WithArrayElements(names ...string) *XMLDecoder {
+	if d.arrays == nil {
+		d.arrays = make(map[string]bool)
+	}
+	for _, name := range names {
+		d.arrays[name] = true
+	}
+	return d
+}
+
+// Root returns the name of the root element read last.
+func (d *XMLDecoder) Root() string {
+	return d.root
+}
+
+// Decode reads the next root element, it returns io.EOF when there are no
+// more.
+func (d *XMLDecoder) Decode() (*Object, error) {
+	for {
+		tok, err := d.decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			d.root = start.Name.Local
+			return d.decodeRoot(start)
+		}
+	}
+}
+
+func (d *XMLDecoder) decodeRoot(start xml.StartElement) (*Object, error) {
+	val, err := d.decodeElement(start)
+	if err != nil {
+		return nil, err
+	}
+	switch val.GetKind() {
+	case ValueKind_VALUE_KIND_OBJECT:
+		return val.GetObject(), nil
+	case ValueKind_VALUE_KIND_NULL:
+		return NewObject(), nil
+	default:
+		return NewObject().SetValue(d.textKey, val), nil
+	}
+}
+
+func (d *XMLDecoder) decodeElement(start xml.StartElement) (*Value, error) {
+	obj := NewObject()
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
+		obj.Vals[d.attrPrefix+attr.Name.Local] = d.scalar(attr.Value)
+	}
+
+	var text strings.Builder
+	for {
+		tok, err := d.decoder.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			child, err := d.decodeElement(t)
+			if err != nil {
+				return nil, err
+			}
+			d.addChild(obj, t.Name.Local, child)
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			s := strings.TrimSpace(text.String())
+			switch {
+			case len(obj.Vals) > 0:
+				if len(s) > 0 {
+					obj.Vals[d.textKey] = d.scalar(s)
+				}
+				return NewObjectValue(obj), nil
+			case len(s) > 0:
+				return d.scalar(s), nil
+			default:
+				return NewNullValue(), nil
+			}
+		}
+	}
+}
+
+func (d *XMLDecoder) addChild(obj *Object, name string, child *Value) {
+	existing, ok := obj.Vals[name]
+	switch {
+	case ok && existing.GetKind() == ValueKind_VALUE_KIND_ARRAY:
+		// children are never arrays themselves, so it holds the repeated ones
+		existing.GetValuesValue().Vals = append(existing.GetValuesValue().Vals, child)
+	case ok:
+		obj.Vals[name] = NewArrayValue(existing, child)
+	case d.arrays[name]:
+		obj.Vals[name] = NewArrayValue(child)
+	default:
+		obj.Vals[name] = child
+	}
+}
+
+func (d *XMLDecoder) scalar(s string) *Value {
+	if d.inferTypes {
+		return inferScalarValue(s)
+	}
+	return NewStringValue(s)
+}
+
+// DecodeXML decodes the root element of data with the default conventions.
+func DecodeXML(data []byte) (*Object, error) {
+	return NewXMLDecoder(bytes.NewReader(data)).Decode()
+}
+
+// XMLEncoder writes Objects as XML elements, see XMLDecoder for the mapping.
+type XMLEncoder struct {
+	encoder    *xml.Encoder
+	root       string
+	attrPrefix string
+	textKey    string
+}
+
+func NewXMLEncoder(w io.Writer) *XMLEncoder {
+	return &XMLEncoder{
+		encoder:    xml.NewEncoder(w),
+		root:       DefaultXMLRoot,
+		attrPrefix: DefaultXMLAttrPrefix,
+		textKey:    DefaultXMLTextKey,
+	}
+}
+
+// WithRoot sets the name of the root element, "root" by default.
+func (e *XMLEncoder) WithRoot(name string) *XMLEncoder {
+	e.root = name
+	return e
+}
+
+func (e *XMLEncoder) WithAttrPrefix(prefix string) *XMLEncoder {
+	e.attrPrefix = prefix
+	return e
+}
+
+func (e *XMLEncoder) WithTextKey(key string) *XMLEncoder {
+	e.textKey = key
+	return e
+}
+
+// WithIndent indents the elements as xml.Encoder.Indent does.
+func (e *XMLEncoder) WithIndent(prefix, indent string) *XMLEncoder {
+	e.encoder.Indent(prefix, indent)
+	return e
+}
+
+// Encode writes obj as the root element.
+func (e *XMLEncoder) Encode(obj *Object) error {
+	if err := e.encodeElement(xml.StartElement{Name: xml.Name{Local: e.root}}, NewObjectValue(obj)); err != nil {
+		return err
+	}
+	return e.encoder.Flush()
+}
+
+func (e *XMLEncoder) encodeElement(start xml.StartElement, val *Value) error {
+	if !isXMLName(start.Name.Local) {
+		return fmt.Errorf("%q is not an XML element name", start.Name.Local)
+	}
+	if val.GetKind() != ValueKind_VALUE_KIND_OBJECT {
+		if err := e.encoder.EncodeToken(start); err != nil {
+			return err
+		}
+		if !isNullValue(val) {
+			text, err := xmlText(val)
+			if err != nil {
+				return fmt.Errorf("element %s: %w", start.Name.Local, err)
+			}
+			if err := e.encoder.EncodeToken(xml.CharData(text)); err != nil {
+				return err
+			}
+		}
+		return e.encoder.EncodeToken(start.End())
+	}
+
+	vals := val.GetObject().GetVals()
+	keys := sortedKeys(vals)
+	var children []string
+	for _, key := range keys {
+		switch {
+		case key == e.textKey:
+		case len(e.attrPrefix) > 0 && strings.HasPrefix(key, e.attrPrefix):
+			if isNullValue(vals[key]) {
+				continue
+			}
+			name := key[len(e.attrPrefix):]
+			if !isXMLName(name) {
+				return fmt.Errorf("%q is not an XML attribute name", name)
+			}
+			text, err := xmlText(vals[key])
+			if err != nil {
+				return fmt.Errorf("attribute %s of %s: %w", name, start.Name.Local, err)
+			}
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: name}, Value: text})
+		default:
+			children = append(children, key)
+		}
+	}
+
+	if err := e.encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	if text, ok := vals[e.textKey]; ok && !isNullValue(text) {
+		s, err := xmlText(text)
+		if err != nil {
+			return fmt.Errorf("text of %s: %w", start.Name.Local, err)
+		}
+		if err := e.encoder.EncodeToken(xml.CharData(s)); err != nil {
+			return err
+		}
+	}
+	for _, key := range children {
+		child := xml.StartElement{Name: xml.Name{Local: key}}
+		if vals[key].GetKind() != ValueKind_VALUE_KIND_ARRAY {
+			if err := e.encodeElement(child, vals[key]); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, v := range vals[key].GetValues() {
+			if v.GetKind() == ValueKind_VALUE_KIND_ARRAY {
+				return fmt.Errorf("element %s: arrays of arrays can not be encoded as XML", key)
+			}
+			if err := e.encodeElement(child, v); err != nil {
+				return err
+			}
+		}
+	}
+	return e.encoder.EncodeToken(start.End())
+}
+
+// isXMLName reports whether name is an XML name without a namespace prefix.
+func isXMLName(name string) bool {
+	if len(name) == 0 {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_' || unicode.IsLetter(r):
+		case i > 0 && (r == '-' || r == '.' || unicode.IsDigit(r)):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// xmlText returns the text of a scalar value, bytes are base64 encoded.
+func xmlText(val *Value) (string, error) {
+	switch val.GetKind() {
+	case ValueKind_VALUE_KIND_OBJECT, ValueKind_VALUE_KIND_ARRAY:
+		return "", fmt.Errorf("%s value is not a scalar", val.GetKind())
+	case ValueKind_VALUE_KIND_BYTES:
+		return base64.StdEncoding.EncodeToString(val.GetBytes()), nil
+	}
+	s, _, err := CoerceJSONLenient.ToString(val)
+	return s, err
+}
+
+// EncodeXML encodes obj as the root element with the default conventions.
+func EncodeXML(obj *Object, root string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := NewXMLEncoder(buf).WithRoot(root).Encode(obj); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalXML encodes x as the element start with the default conventions,
+// so that an Object can be a field of a struct marshaled by "encoding/xml".
+func (x *Object) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return (&XMLEncoder{encoder: e, attrPrefix: DefaultXMLAttrPrefix, textKey: DefaultXMLTextKey}).encodeElement(start, NewObjectValue(x))
+}
+
+// UnmarshalXML decodes the element start into x with the default conventions.
+func (x *Object) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	obj, err := (&XMLDecoder{decoder: d, attrPrefix: DefaultXMLAttrPrefix, textKey: DefaultXMLTextKey}).decodeRoot(start)
+	if err != nil {
+		return err
+	}
+	x.Vals = obj.Vals
+	return nil
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+const xmlTestOrder = `<?xml version="1.0" encoding="UTF-8"?>
+<!-- legacy order -->
+<order xmlns="urn:orders" id="7" paid="true">
+  <item sku="a1">apple</item>
+  <item sku="b2">banana</item>
+  <total>12.5</total>
+  <note lang="en">
+    handle with care
+  </note>
+  <customer><name>Ann</name><zip>007</zip></customer>
+  <empty/>
+</order>`
+
+func TestXMLDecoder_Decode(t *testing.T) {
+	dec := NewXMLDecoder(strings.NewReader(xmlTestOrder))
+	obj, err := dec.Decode()
+	assert.NoError(t, err)
+	assert.Equal(t, "order", dec.Root())
+
+	s, _ := jsoniter.MarshalToString(obj)
+	assert.Equal(t, `{"@id":"7","@paid":"true","customer":{"name":"Ann","zip":"007"},"empty":null,`+
+		`"item":[{"#text":"apple","@sku":"a1"},{"#text":"banana","@sku":"b2"}],`+
+		`"note":{"#text":"handle with care","@lang":"en"},"total":"12.5"}`, s)
+
+	_, err = dec.Decode()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestXMLDecoder_Options(t *testing.T) {
+	obj, err := NewXMLDecoder(strings.NewReader(xmlTestOrder)).
+		WithAttrPrefix("_").
+		WithTextKey("value").
+		WithInferTypes(true).
+		WithArrayElements("customer").
+		Decode()
+	assert.NoError(t, err)
+
+	assert.Equal(t, int64(7), obj.GetValue("_id").GetInt64())
+	assert.Equal(t, true, obj.GetValue("_paid").GetBool())
+	assert.Equal(t, 12.5, obj.GetValue("total").GetFloat64())
+	assert.Equal(t, "apple", obj.GetValue("item").GetPath("0.value").GetString())
+	assert.Equal(t, ValueKind_VALUE_KIND_ARRAY, obj.GetValue("customer").GetKind())
+	assert.Equal(t, int64(7), obj.GetValue("customer").GetPath("0.zip").GetInt64())
+}
+
+func TestXMLDecoder_Errors(t *testing.T) {
+	_, err := DecodeXML([]byte(`<a><b></a>`))
+	assert.Error(t, err)
+
+	_, err = DecodeXML([]byte(`<a><b>`))
+	assert.Error(t, err)
+
+	obj, err := DecodeXML([]byte(`<a>text</a>`))
+	assert.NoError(t, err)
+	assert.Equal(t, "text", obj.GetString(DefaultXMLTextKey))
+}
+
+func TestXMLEncoder_Encode(t *testing.T) {
+	obj := NewObject().
+		SetString("@id", "7").
+		SetValue("item", NewArrayValue(
+			NewObjectValue(NewObject().SetString("@sku", "a1").SetString("#text", "apple")),
+			NewStringValue("banana"),
+		)).
+		SetFloat64("total", 12.5).
+		SetValue("data", NewBytesValue([]byte{1, 2, 3})).
+		SetValue("empty", NewNullValue()).
+		SetString("escaped", "a<b & c")
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, NewXMLEncoder(buf).WithRoot("order").WithIndent("", "  ").Encode(obj))
+	assert.Equal(t, `<order id="7">
+  <data>AQID</data>
+  <empty></empty>
+  <escaped>a&lt;b &amp; c</escaped>
+  <item sku="a1">apple</item>
+  <item>banana</item>
+  <total>12.5</total>
+</order>`, buf.String())
+
+	_, err := EncodeXML(NewObject().SetValue("nested", NewArrayValue(NewArrayValue())), "root")
+	assert.Error(t, err)
+	_, err = EncodeXML(NewObject().SetValue("@attr", NewObjectValue(NewObject())), "root")
+	assert.Error(t, err)
+	_, err = EncodeXML(NewObject().SetString("not a name", "x"), "root")
+	assert.Error(t, err)
+}
+
+func TestXML_RoundTrip(t *testing.T) {
+	obj := NewObject().
+		SetInt("@id", 7).
+		SetBool("@paid", true).
+		SetValue("item", NewArrayValue(
+			NewObjectValue(NewObject().SetString("@sku", "a1").SetString("#text", "apple")),
+			NewObjectValue(NewObject().SetString("@sku", "b2").SetFloat64("price", 1.5)),
+		)).
+		SetValue("tag", NewArrayValue(NewStringValue("fresh"))).
+		SetObject("customer", NewObject().SetString("name", "Ann").SetValue("phone", NewNullValue()))
+
+	data, err := EncodeXML(obj, "order")
+	assert.NoError(t, err)
+
+	decoded, err := NewXMLDecoder(bytes.NewReader(data)).WithInferTypes(true).WithArrayElements("tag").Decode()
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(obj, decoded), "%s", data)
+
+	// strings only, without type inference
+	strs := NewObject().SetString("@id", "007").SetValue("line", NewArrayValue(NewStringValue("a"), NewStringValue("b")))
+	data, err = EncodeXML(strs, "root")
+	assert.NoError(t, err)
+	decoded, err = DecodeXML(data)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(strs, decoded), "%s", data)
+}
+
+func TestObject_MarshalXML(t *testing.T) {
+	type envelope struct {
+		XMLName xml.Name `xml:"envelope"`
+		Version string   `xml:"version,attr"`
+		Body    *Object  `xml:"body"`
+	}
+
+	env := &envelope{Version: "1", Body: NewObject().SetString("@type", "ping").SetString("from", "a")}
+	data, err := xml.Marshal(env)
+	assert.NoError(t, err)
+	assert.Equal(t, `<envelope version="1"><body type="ping"><from>a</from></body></envelope>`, string(data))
+
+	decoded := &envelope{}
+	assert.NoError(t, xml.Unmarshal(data, decoded))
+	assert.True(t, proto.Equal(env.Body, decoded.Body))
+}
@@ -0,0 +1,299 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// MaskMode selects how a Redactor masks a sensitive value.
+type MaskMode int
+
+const (
+	// MaskFull replaces the value with a fixed mask, hiding its length.
+	MaskFull MaskMode = iota
+	// MaskPartial keeps the last four characters of values long enough to
+	// not reveal them, e.g. "************1111".
+	MaskPartial
+	// MaskHash replaces the value with a short sha256 digest, so equal
+	// secrets can still be correlated across log lines.
+	MaskHash
+)
+
+const RedactedMask = "******"
+
+// Detector finds sensitive substrings in string values regardless of their
+// key, e.g. credit card numbers in a free-form comment.
+type Detector struct {
+	Name    string
+	Pattern *regexp.Regexp
+	// Validate filters the matches of Pattern, nil accepts all of them.
+	Validate func(match string) bool
+}
+
+var (
+	CreditCardDetector = &Detector{
+		Name:     "credit_card",
+		Pattern:  regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		Validate: luhnValid,
+	}
+	JWTDetector = &Detector{
+		Name:    "jwt",
+		Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	}
+	EmailDetector = &Detector{
+		Name:    "email",
+		Pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	}
+)
+
+// DefaultRedactKeys are the key patterns of DefaultRedactor.
+var DefaultRedactKeys = []string{"password", "passwd", "*secret*", "*token*", "authorization", "cookie", "*api_key*", "*apikey*", "*private_key*"}
+
+// Redactor masks sensitive data in Values, Objects and Error details. A leaf
+// value is sensitive when one of the keys on its path matches a key pattern,
+// when its path is below one of the configured paths, or, for strings, when a
+// detector matches part of it; only the matched part is masked then.
+//
+// Key patterns are shell globs (see path.Match) compared case-insensitively,
+// e.g. "*token*". Paths are dotted, see Value.GetPath, and may use "*" for
+// any single segment, e.g. "users.*.ssn".
+type Redactor struct {
+	keys      []string
+	paths     [][]string
+	detectors []*Detector
+	mode      MaskMode
+}
+
+func NewRedactor() *Redactor {
+	return &Redactor{}
+}
+
+// DefaultRedactor returns a Redactor for DefaultRedactKeys, credit card
+// numbers, JWTs and emails, with full masking.
+func DefaultRedactor() *Redactor {
+	return NewRedactor().
+		WithKeys(DefaultRedactKeys...).
+		WithDetectors(CreditCardDetector, JWTDetector, EmailDetector)
+}
+
+func (r *Redactor) WithKeys(patterns ...string) *Redactor {
+	for _, p := range patterns {
+		r.keys = append(r.keys, strings.ToLower(p))
+	}
+	return r
+}
+
+func (r *Redactor) WithPaths(paths ...string) *Redactor {
+	for _, p := range paths {
+		r.paths = append(r.paths, splitPath(p))
+	}
+	return r
+}
+
+func (r *Redactor) WithDetectors(detectors ...*Detector) *Redactor {
+	r.detectors = append(r.detectors, detectors...)
+	return r
+}
+
+func (r *Redactor) WithMask(mode MaskMode) *Redactor {
+	r.mode = mode
+	return r
+}
+
+// IsSensitiveKey reports whether key matches one of the key patterns.
+func (r *Redactor) IsSensitiveKey(key string) bool {
+	if r == nil {
+		return false
+	}
+	key = strings.ToLower(key)
+	for _, pattern := range r.keys {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Mask masks a whole value with the configured MaskMode.
+func (r *Redactor) Mask(s string) string {
+	if r == nil {
+		return s
+	}
+	switch r.mode {
+	case MaskPartial:
+		if n := utf8.RuneCountInString(s); n >= 12 {
+			runes := []rune(s)
+			return strings.Repeat("*", n-4) + string(runes[n-4:])
+		}
+		return RedactedMask
+	case MaskHash:
+		sum := sha256.Sum256([]byte(s))
+		return "sha256:" + hex.EncodeToString(sum[:8])
+	default:
+		return RedactedMask
+	}
+}
+
+// RedactString masks the parts of s found by the detectors.
+func (r *Redactor) RedactString(s string) string {
+	if r == nil {
+		return s
+	}
+	for _, d := range r.detectors {
+		s = d.Pattern.ReplaceAllStringFunc(s, func(match string) string {
+			if d.Validate != nil && !d.Validate(match) {
+				return match
+			}
+			return r.Mask(match)
+		})
+	}
+	return s
+}
+
+// RedactValue returns a redacted copy of v, v itself is left untouched.
+func (r *Redactor) RedactValue(v *Value) *Value {
+	if r == nil || v == nil {
+		return v
+	}
+	val, _ := Transform(v, func(p string, val *Value) (*Value, error) {
+		switch val.GetKind() {
+		case ValueKind_VALUE_KIND_OBJECT, ValueKind_VALUE_KIND_ARRAY, ValueKind_VALUE_KIND_NULL:
+			return val, nil
+		}
+		if r.isSensitivePath(splitPath(p)) {
+			return NewStringValue(r.Mask(valueText(val))), nil
+		}
+		if val.GetKind() == ValueKind_VALUE_KIND_STRING {
+			if s := r.RedactString(val.GetString()); s != val.GetString() {
+				return NewStringValue(s), nil
+			}
+		}
+		return val, nil
+	})
+	return val
+}
+
+// RedactObject returns a redacted copy of obj.
+func (r *Redactor) RedactObject(obj *Object) *Object {
+	if r == nil || obj == nil {
+		return obj
+	}
+	return r.RedactValue(NewObjectValue(obj)).GetObject()
+}
+
+// RedactError returns a copy of err with its details redacted and the
+// detectors applied to its message.
+func (r *Redactor) RedactError(err *Error) *Error {
+	if r == nil || err == nil {
+		return err
+	}
+	redacted := proto.Clone(err).(*Error)
+	redacted.Message = r.RedactString(err.Message)
+	for i, detail := range redacted.Details {
+		redacted.Details[i] = r.RedactValue(detail)
+	}
+	return redacted
+}
+
+// RedactField redacts a key/value pair as found in structured logs. Values
+// under a sensitive key are masked as a whole, Values, Objects, Errors,
+// strings and generic maps and slices are redacted, anything else is
+// returned as it is.
+func (r *Redactor) RedactField(key string, val any) any {
+	if r == nil || val == nil {
+		return val
+	}
+	if r.IsSensitiveKey(key) {
+		return r.Mask(fmt.Sprint(val))
+	}
+
+	switch v := val.(type) {
+	case string:
+		return r.RedactString(v)
+	case *Value:
+		return r.RedactValue(v)
+	case *Object:
+		return r.RedactObject(v)
+	case *Error:
+		return r.RedactError(v)
+	case map[string]any:
+		if obj, err := NewObjectFromMap(v); err == nil {
+			return r.RedactObject(obj).AsMap()
+		}
+	case []any:
+		if vals, err := NewValues(v); err == nil {
+			return r.RedactValue(NewValuesValue(vals)).AsInterface()
+		}
+	}
+	return val
+}
+
+func (r *Redactor) isSensitivePath(segments []string) bool {
+	for _, segment := range segments {
+		if _, err := strconv.Atoi(segment); err == nil {
+			continue
+		}
+		if r.IsSensitiveKey(segment) {
+			return true
+		}
+	}
+
+	for _, p := range r.paths {
+		if len(p) > len(segments) {
+			continue
+		}
+		matched := true
+		for i, s := range p {
+			if s != "*" && s != segments[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func valueText(v *Value) string {
+	switch v.GetKind() {
+	case ValueKind_VALUE_KIND_STRING:
+		return v.GetString()
+	case ValueKind_VALUE_KIND_BYTES:
+		return string(v.GetBytes())
+	default:
+		bs, _ := v.Value()
+		s, _ := bs.(string)
+		return s
+	}
+}
+
+func luhnValid(number string) bool {
+	sum, count, double := 0, 0, false
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		count++
+		double = !double
+	}
+	return count >= 13 && sum%10 == 0
+}
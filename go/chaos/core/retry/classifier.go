@@ -0,0 +1,60 @@
+package retry
+
+import "github.com/chaos-io/core/go/chaos/core"
+
+type codeKey struct {
+	domain string
+	code   int32
+}
+
+// Classifier decides whether an error is retryable by the code of its
+// core.Error. Codes are configured one by one, or for their whole domain.
+type Classifier struct {
+	codes   map[codeKey]bool
+	domains map[string]bool
+}
+
+// NewClassifier returns a classifier retrying Unavailable, ResourceExhausted,
+// Aborted and DeadlineExceeded.
+func NewClassifier() *Classifier {
+	return (&Classifier{codes: map[codeKey]bool{}, domains: map[string]bool{}}).
+		WithRetryable(core.Unavailable, core.ResourceExhausted, core.Aborted, core.DeadlineExceeded)
+}
+
+// WithRetryable makes the codes retryable.
+func (c *Classifier) WithRetryable(codes ...*core.ErrorCode) *Classifier {
+	for _, code := range codes {
+		c.codes[codeKey{domain: code.GetDomain(), code: code.GetCode()}] = true
+	}
+	return c
+}
+
+// WithoutRetryable makes the codes not retryable, even if their domain is
+// retryable, see WithDomain.
+func (c *Classifier) WithoutRetryable(codes ...*core.ErrorCode) *Classifier {
+	for _, code := range codes {
+		c.codes[codeKey{domain: code.GetDomain(), code: code.GetCode()}] = false
+	}
+	return c
+}
+
+// WithDomain makes the codes of domain which are not configured one by one
+// retryable or not.
+func (c *Classifier) WithDomain(domain string, retryable bool) *Classifier {
+	c.domains[domain] = retryable
+	return c
+}
+
+// IsRetryable reports whether the first core.Error in the chain of err has a
+// retryable code. Errors without core.Error, like context.Canceled, are not
+// retryable.
+func (c *Classifier) IsRetryable(err error) bool {
+	e := core.AsError(err)
+	if e == nil || e.Code == nil {
+		return false
+	}
+	if retryable, ok := c.codes[codeKey{domain: e.Code.Domain, code: e.Code.Code}]; ok {
+		return retryable
+	}
+	return c.domains[e.Code.Domain]
+}
@@ -0,0 +1,30 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/chaos-io/core/go/chaos/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifier(t *testing.T) {
+	declined := &core.ErrorCode{Domain: "test.payments", Code: 1, Name: "DECLINED"}
+	busy := &core.ErrorCode{Domain: "test.payments", Code: 2, Name: "BUSY"}
+	other := &core.ErrorCode{Domain: "test.shipping", Code: 1, Name: "LATE"}
+
+	classifier := NewClassifier().WithDomain("test.payments", true).WithoutRetryable(declined, core.Aborted)
+	assert.True(t, classifier.IsRetryable(core.NewError(core.Unavailable, "")))
+	assert.True(t, classifier.IsRetryable(core.NewDeadlineExceededError("timeout")))
+	assert.True(t, classifier.IsRetryable(core.Wrap(errors.New("connection reset"), busy, "charge")))
+	assert.False(t, classifier.IsRetryable(core.NewError(declined, "")))
+	assert.False(t, classifier.IsRetryable(core.NewError(core.Aborted, "")))
+	assert.False(t, classifier.IsRetryable(core.NewError(other, "")))
+	assert.False(t, classifier.IsRetryable(core.NewError(core.InvalidArgument, "")))
+	assert.False(t, classifier.IsRetryable(errors.New("plain")))
+	assert.False(t, classifier.IsRetryable(context.DeadlineExceeded))
+
+	// codes decoded off the wire match too
+	assert.True(t, NewClassifier().IsRetryable(core.NewErrorFrom(503, "down")))
+}
@@ -0,0 +1,194 @@
+// Package retry retries operations failing with retryable core.Errors, with
+// exponential backoff and the retry delays sent by servers.
+package retry
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/chaos-io/core/go/chaos/core"
+)
+
+// The defaults of NewPolicy.
+const (
+	DefaultMaxAttempts    = 5
+	DefaultInitialBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff     = 10 * time.Second
+	DefaultMultiplier     = 2.0
+	DefaultJitter         = 0.2
+)
+
+// Clock is the time Do waits by, replaced by fake clocks in tests.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// SystemClock returns the clock of the system time.
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+// Policy decides how often and after which delays Do retries.
+type Policy struct {
+	maxAttempts    int
+	initialBackoff *core.Duration
+	maxBackoff     *core.Duration
+	multiplier     float64
+	jitter         float64
+	classifier     *Classifier
+	clock          Clock
+	random         func() float64
+}
+
+// NewPolicy returns a policy of the default values, retrying the errors
+// NewClassifier classifies as retryable.
+func NewPolicy() *Policy {
+	return &Policy{
+		maxAttempts:    DefaultMaxAttempts,
+		initialBackoff: core.FromDuration(DefaultInitialBackoff),
+		maxBackoff:     core.FromDuration(DefaultMaxBackoff),
+		multiplier:     DefaultMultiplier,
+		jitter:         DefaultJitter,
+		classifier:     NewClassifier(),
+		clock:          SystemClock(),
+		random:         rand.Float64,
+	}
+}
+
+// WithMaxAttempts sets how often fn is called at most, including the first
+// call.
+func (p *Policy) WithMaxAttempts(maxAttempts int) *Policy {
+	p.maxAttempts = maxAttempts
+	return p
+}
+
+// WithBackoff sets the backoff before the first retry, and the one the
+// backoff grows up to, which is unlimited if nil or zero.
+func (p *Policy) WithBackoff(initial *core.Duration, maxBackoff *core.Duration) *Policy {
+	p.initialBackoff = initial
+	p.maxBackoff = maxBackoff
+	return p
+}
+
+// WithMultiplier sets the factor the backoff grows by on every retry.
+func (p *Policy) WithMultiplier(multiplier float64) *Policy {
+	p.multiplier = multiplier
+	return p
+}
+
+// WithJitter sets the fraction of the backoff it randomly varies by, 0.2 for
+// ±20%, so that clients failing together do not retry together.
+func (p *Policy) WithJitter(jitter float64) *Policy {
+	p.jitter = jitter
+	return p
+}
+
+func (p *Policy) WithClassifier(classifier *Classifier) *Policy {
+	p.classifier = classifier
+	return p
+}
+
+func (p *Policy) WithClock(clock Clock) *Policy {
+	p.clock = clock
+	return p
+}
+
+// WithRandom sets the source of the jitter, returning numbers in [0, 1).
+func (p *Policy) WithRandom(random func() float64) *Policy {
+	p.random = random
+	return p
+}
+
+// Backoff returns the backoff before the retry following attempt, 1 for the
+// first call: the initial backoff times multiplier^(attempt-1), varied by
+// the jitter and capped by the max backoff.
+func (p *Policy) Backoff(attempt int) *core.Duration {
+	backoff := float64(toDuration(p.initialBackoff)) * math.Pow(p.multiplier, float64(max(attempt-1, 0)))
+	if p.jitter > 0 {
+		backoff *= 1 + p.jitter*(2*p.random()-1)
+	}
+	return core.FromDuration(p.clamp(backoff))
+}
+
+// Delay returns the delay before the retry following attempt failing with
+// err, the retry delay of the RetryInfo of its core.Error if the server sent
+// one, or Backoff. The retry delay is capped by the max backoff too.
+func (p *Policy) Delay(attempt int, err error) *core.Duration {
+	if info := core.AsError(err).RetryInfo(); info.GetRetryDelay() != nil {
+		return core.FromDuration(p.clamp(float64(info.GetRetryDelay().ToDuration())))
+	}
+	return p.Backoff(attempt)
+}
+
+// clamp returns the delay of d nanoseconds, at least 0 and at most the max
+// backoff, or the longest Duration if there is no max backoff.
+func (p *Policy) clamp(d float64) time.Duration {
+	limit := toDuration(p.maxBackoff)
+	if limit <= 0 {
+		limit = math.MaxInt64
+	}
+	switch {
+	case math.IsNaN(d) || d <= 0:
+		return 0
+	case d >= float64(limit):
+		return limit
+	}
+	return time.Duration(d)
+}
+
+// toDuration returns d as a time.Duration, 0 for a nil d.
+func toDuration(d *core.Duration) time.Duration {
+	if d == nil {
+		return 0
+	}
+	return d.ToDuration()
+}
+
+// IsRetryable reports whether err is retryable, see Classifier.IsRetryable.
+func (p *Policy) IsRetryable(err error) bool {
+	return p.classifier.IsRetryable(err)
+}
+
+// Do calls fn until it succeeds, fails with an error which is not retryable,
+// or the policy runs out of attempts, waiting Delay between the calls. It
+// stops early when ctx is done or its deadline would pass during the delay.
+// It returns the error of the last call, or the one of ctx if it is done
+// before the first call. A nil policy is NewPolicy().
+func Do(ctx context.Context, policy *Policy, fn func(ctx context.Context) error) error {
+	if policy == nil {
+		policy = NewPolicy()
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil || attempt >= policy.maxAttempts || !policy.IsRetryable(err) {
+			return err
+		}
+
+		delay := policy.Delay(attempt, err).ToDuration()
+		if deadline, ok := ctx.Deadline(); ok && policy.clock.Now().Add(delay).After(deadline) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-policy.clock.After(delay):
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package retry
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/chaos-io/core/go/chaos/core"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock fires every timer at once, advancing its time by the waited
+// durations.
+type fakeClock struct {
+	now    time.Time
+	waited []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.waited = append(c.waited, d)
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func TestPolicy_Backoff(t *testing.T) {
+	policy := NewPolicy().WithJitter(0).WithBackoff(core.FromDuration(time.Second), core.FromDuration(5*time.Second))
+	var backoffs []time.Duration
+	for attempt := 1; attempt <= 5; attempt++ {
+		backoffs = append(backoffs, policy.Backoff(attempt).ToDuration())
+	}
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}, backoffs)
+
+	random := 0.0
+	policy.WithJitter(0.5).WithRandom(func() float64 { return random })
+	assert.Equal(t, 500*time.Millisecond, policy.Backoff(1).ToDuration())
+	random = 0.75
+	assert.Equal(t, 1250*time.Millisecond, policy.Backoff(1).ToDuration())
+	assert.Equal(t, 5*time.Second, policy.Backoff(3).ToDuration())
+
+	err := core.NewError(core.Unavailable, "down").AddRetryInfo(core.FromDuration(30 * time.Second))
+	assert.Equal(t, 5*time.Second, policy.Delay(1, err).ToDuration())
+	err = core.NewError(core.Unavailable, "down").AddRetryInfo(core.FromDuration(3 * time.Second))
+	assert.Equal(t, 3*time.Second, policy.Delay(1, err).ToDuration())
+	err = core.NewError(core.Unavailable, "down").AddRetryInfo(core.FromDuration(-time.Second))
+	assert.Equal(t, time.Duration(0), policy.Delay(1, err).ToDuration())
+	assert.Equal(t, 1250*time.Millisecond, policy.Delay(1, core.NewError(core.Unavailable, "down")).ToDuration())
+
+	// without max backoff the backoff grows up to the longest Duration
+	policy = NewPolicy().WithJitter(0).WithBackoff(core.FromDuration(time.Second), nil)
+	assert.Equal(t, 16*time.Second, policy.Backoff(5).ToDuration())
+	assert.Equal(t, time.Duration(math.MaxInt64), policy.Backoff(1000).ToDuration())
+	err = core.NewError(core.Unavailable, "down").AddRetryInfo(core.FromDuration(time.Hour))
+	assert.Equal(t, time.Hour, policy.Delay(1, err).ToDuration())
+}
+
+func TestDo(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	calls := 0
+	err := Do(context.Background(), NewPolicy().WithClock(clock).WithJitter(0), func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return core.NewUnavailableError("down")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, clock.waited)
+
+	// the server retry delay is honored
+	clock = &fakeClock{now: time.Unix(0, 0)}
+	calls = 0
+	err = Do(context.Background(), NewPolicy().WithClock(clock).WithJitter(0).WithMaxAttempts(2), func(ctx context.Context) error {
+		calls++
+		return core.NewError(core.ResourceExhausted, "slow down").AddRetryInfo(core.FromDuration(3 * time.Second))
+	})
+	assert.True(t, core.HasCode(err, core.ResourceExhausted))
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, []time.Duration{3 * time.Second}, clock.waited)
+
+	// errors which are not retryable are returned at once
+	calls = 0
+	err = Do(context.Background(), NewPolicy().WithClock(clock).WithJitter(0), func(ctx context.Context) error {
+		calls++
+		return core.NewNotFoundError("gone")
+	})
+	assert.True(t, core.IsNotFoundError(err))
+	assert.Equal(t, 1, calls)
+}
+
+func TestDo_Context(t *testing.T) {
+	// contexts have deadlines of the system time
+	clock := &fakeClock{now: time.Now()}
+	unavailable := func(ctx context.Context) error {
+		return core.NewError(core.Unavailable, "down").AddRetryInfo(core.FromDuration(2 * time.Second))
+	}
+
+	// the deadline would pass during the delay
+	ctx, cancel := context.WithDeadline(context.Background(), clock.now.Add(time.Second))
+	defer cancel()
+	err := Do(ctx, NewPolicy().WithClock(clock).WithJitter(0), unavailable)
+	assert.True(t, core.IsUnavailableError(err))
+	assert.Empty(t, clock.waited)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	err = Do(ctx, NewPolicy().WithClock(clock).WithJitter(0), unavailable)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// canceled between the attempts
+	ctx, cancel = context.WithCancel(context.Background())
+	calls := 0
+	err = Do(ctx, NewPolicy().WithClock(blockingClock{clock}), func(ctx context.Context) error {
+		calls++
+		cancel()
+		return core.NewAbortedError("conflict")
+	})
+	assert.True(t, core.IsAbortedError(err))
+	assert.Equal(t, 1, calls)
+}
+
+// blockingClock never fires its timers.
+type blockingClock struct {
+	*fakeClock
+}
+
+func (blockingClock) After(time.Duration) <-chan time.Time {
+	return nil
+}
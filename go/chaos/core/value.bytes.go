@@ -0,0 +1,122 @@
+package core
+
+import (
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+)
+
+// BytesJSONMode selects how BYTES values are encoded in JSON. Both forms are
+// always accepted when decoding.
+type BytesJSONMode int32
+
+const (
+	// BytesJSONPrefix encodes bytes as a base64 string prefixed by
+	// Base64Prefix, e.g. "b64.AQID".
+	BytesJSONPrefix BytesJSONMode = iota
+	// BytesJSONWrapper encodes bytes as an object holding the base64 string
+	// under BytesJSONKey, e.g. {"$bytes":"AQID"}, which can not be mistaken
+	// for a string.
+	BytesJSONWrapper
+)
+
+// BytesJSONKey is the only key of the object of BytesJSONWrapper.
+const BytesJSONKey = "$bytes"
+
+var bytesJSONMode atomic.Int32
+
+// SetBytesJSONMode sets how BYTES values are encoded in JSON, the default is
+// BytesJSONPrefix.
+func SetBytesJSONMode(mode BytesJSONMode) {
+	bytesJSONMode.Store(int32(mode))
+}
+
+// GetBytesJSONMode returns the mode set by SetBytesJSONMode.
+func GetBytesJSONMode() BytesJSONMode {
+	return BytesJSONMode(bytesJSONMode.Load())
+}
+
+// decodeBytesString decodes a string in the BytesJSONPrefix form.
+func decodeBytesString(s string) ([]byte, bool, error) {
+	if !strings.HasPrefix(s, Base64Prefix) {
+		return nil, false, nil
+	}
+	bs, err := base64.StdEncoding.DecodeString(s[len(Base64Prefix):])
+	return bs, true, err
+}
+
+// decodeBytesWrapper decodes an object in the BytesJSONWrapper form.
+func decodeBytesWrapper(a jsoniter.Any) ([]byte, bool, error) {
+	if a.Size() != 1 {
+		return nil, false, nil
+	}
+	encoded := a.Get(BytesJSONKey)
+	if encoded.ValueType() != jsoniter.StringValue {
+		return nil, false, nil
+	}
+	bs, err := base64.StdEncoding.DecodeString(encoded.ToString())
+	return bs, true, err
+}
+
+// objectToAPI is the config of Object.To. It lets []byte fields also decode
+// the BYTES forms of Value besides plain base64 strings.
+var objectToAPI = func() jsoniter.API {
+	api := jsoniter.Config{
+		EscapeHTML:                    false,
+		MarshalFloatWith6Digits:       true,
+		ObjectFieldMustBeSimpleString: true,
+	}.Froze()
+	api.RegisterExtension(&bytesFieldExtension{})
+	return api
+}()
+
+var bytesType = reflect.TypeOf([]byte(nil))
+
+type bytesFieldExtension struct {
+	jsoniter.DummyExtension
+}
+
+func (e *bytesFieldExtension) DecorateDecoder(typ reflect2.Type, decoder jsoniter.ValDecoder) jsoniter.ValDecoder {
+	if typ.Type1() == bytesType {
+		return &bytesFieldDecoder{fallback: decoder}
+	}
+	return decoder
+}
+
+type bytesFieldDecoder struct {
+	fallback jsoniter.ValDecoder
+}
+
+func (d *bytesFieldDecoder) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	switch iter.WhatIsNext() {
+	case jsoniter.StringValue:
+		s := iter.ReadString()
+		bs, ok, err := decodeBytesString(s)
+		if !ok {
+			bs, err = base64.StdEncoding.DecodeString(s)
+		}
+		if err != nil {
+			iter.ReportError("decode []byte", err.Error())
+			return
+		}
+		*(*[]byte)(ptr) = bs
+	case jsoniter.ObjectValue:
+		bs, ok, err := decodeBytesWrapper(iter.ReadAny())
+		if !ok {
+			iter.ReportError("decode []byte", "expect base64 string or "+BytesJSONKey+" object")
+			return
+		}
+		if err != nil {
+			iter.ReportError("decode []byte", err.Error())
+			return
+		}
+		*(*[]byte)(ptr) = bs
+	default:
+		d.fallback.Decode(ptr, iter)
+	}
+}
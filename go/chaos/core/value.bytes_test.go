@@ -0,0 +1,107 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewValue_Bytes(t *testing.T) {
+	val, err := NewValue([]byte{1, 2, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, ValueKind_VALUE_KIND_BYTES, val.GetKind())
+	assert.Equal(t, []byte{1, 2, 3}, val.GetBytes())
+	assert.Equal(t, []byte{1, 2, 3}, val.AsInterface())
+
+	back, err := NewValue(val.AsInterface())
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(val, back))
+}
+
+func TestBytesValue_JSON(t *testing.T) {
+	obj := NewObject().SetValue("data", NewBytesValue([]byte{1, 2, 3})).SetString("name", "x")
+
+	for _, tt := range []struct {
+		mode BytesJSONMode
+		want string
+	}{
+		{BytesJSONPrefix, `{"data":"b64.AQID","name":"x"}`},
+		{BytesJSONWrapper, `{"data":{"$bytes":"AQID"},"name":"x"}`},
+	} {
+		SetBytesJSONMode(tt.mode)
+
+		s, err := jsoniter.MarshalToString(obj)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, s)
+
+		bs, err := json.Marshal(obj)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, string(bs))
+
+		decoded := &Object{}
+		assert.NoError(t, json.Unmarshal([]byte(tt.want), decoded))
+		assert.Equal(t, ValueKind_VALUE_KIND_BYTES, decoded.GetValue("data").GetKind())
+		assert.Equal(t, []byte{1, 2, 3}, decoded.GetValue("data").GetBytes())
+	}
+	SetBytesJSONMode(BytesJSONPrefix)
+
+	vals := NewArrayValue(NewBytesValue([]byte("hi")), NewStringValue("hi")).GetValuesValue()
+	s, err := jsoniter.MarshalToString(vals)
+	assert.NoError(t, err)
+	assert.Equal(t, `["b64.aGk=","hi"]`, s)
+
+	// "encoding/json" keeps the protojson form of Values
+	bs, err := json.Marshal(vals)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"vals":[{"bytesValue":"aGk="},{"stringValue":"hi"}]}`, string(bs))
+
+	decoded := &Values{}
+	assert.NoError(t, json.Unmarshal(bs, decoded))
+	assert.True(t, proto.Equal(vals, decoded))
+	decoded = &Values{}
+	assert.NoError(t, jsoniter.UnmarshalFromString(s, decoded))
+	assert.True(t, proto.Equal(vals, decoded))
+
+	// an object with other keys besides $bytes stays an object
+	val := &Value{}
+	assert.NoError(t, jsoniter.UnmarshalFromString(`{"$bytes":"AQID","more":1}`, val))
+	assert.Equal(t, ValueKind_VALUE_KIND_OBJECT, val.GetKind())
+
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"$bytes":"!"}`, val))
+}
+
+func TestBytesValue_Proto(t *testing.T) {
+	val := NewObjectValue(NewObject().SetValue("data", NewBytesValue([]byte{0, 255})))
+	bs, err := proto.Marshal(val)
+	assert.NoError(t, err)
+
+	decoded := &Value{}
+	assert.NoError(t, proto.Unmarshal(bs, decoded))
+	assert.Equal(t, []byte{0, 255}, decoded.GetObject().GetValue("data").GetBytes())
+}
+
+func TestObject_ToBytes(t *testing.T) {
+	type file struct {
+		Name string `json:"name"`
+		Data []byte `json:"data"`
+	}
+
+	obj := NewObject().SetString("name", "a").SetValue("data", NewBytesValue([]byte{1, 2, 3}))
+	for _, mode := range []BytesJSONMode{BytesJSONPrefix, BytesJSONWrapper} {
+		SetBytesJSONMode(mode)
+		f := &file{}
+		assert.NoError(t, obj.To(f))
+		assert.Equal(t, &file{Name: "a", Data: []byte{1, 2, 3}}, f)
+	}
+	SetBytesJSONMode(BytesJSONPrefix)
+
+	// plain base64 strings, as written by "encoding/json"
+	f := &file{}
+	assert.NoError(t, NewObject().SetString("data", "AQID").To(f))
+	assert.Equal(t, []byte{1, 2, 3}, f.Data)
+
+	assert.Error(t, NewObject().SetString("data", "b64.!").To(f))
+}
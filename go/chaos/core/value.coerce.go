@@ -0,0 +1,510 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CoercionPolicy decides which conversions between Value kinds the typed
+// accessors accept, e.g. whether GetInt64 of StringValue("42") is 42 or an
+// error. Each conversion also reports whether it was lossless.
+//
+//	╔══════════════════════╤════════╤══════════════╤══════════════╗
+//	║ conversion           │ strict │ JSON-lenient │ form-lenient ║
+//	╠══════════════════════╪════════╪══════════════╪══════════════╣
+//	║ integer ↔ number     │ to num │ yes          │ yes          ║
+//	║ string ↔ number      │ no     │ yes          │ yes, "" is 0 ║
+//	║ string ↔ bool        │ no     │ true/false   │ + 1/0/on/yes ║
+//	║ number ↔ bool        │ no     │ no           │ yes          ║
+//	║ RFC3339 ↔ Timestamp  │ yes    │ + unix secs  │ + any layout ║
+//	║ string ↔ Duration    │ yes    │ + seconds    │ + seconds    ║
+//	║ null → zero value    │ no     │ yes          │ yes          ║
+//	╚══════════════════════╧════════╧══════════════╧══════════════╝
+type CoercionPolicy int
+
+const (
+	CoerceStrict CoercionPolicy = iota
+	CoerceJSONLenient
+	CoerceFormLenient
+)
+
+func (p CoercionPolicy) String() string {
+	switch p {
+	case CoerceStrict:
+		return "strict"
+	case CoerceJSONLenient:
+		return "json-lenient"
+	case CoerceFormLenient:
+		return "form-lenient"
+	default:
+		return "unknown"
+	}
+}
+
+func (p CoercionPolicy) errorf(v *Value, target string) error {
+	return fmt.Errorf("can not coerce %s to %s under the %s policy", v.GetKind(), target, p)
+}
+
+// ToInt64 converts v to an int64. Numbers are truncated toward zero.
+func (p CoercionPolicy) ToInt64(v *Value) (val int64, lossless bool, err error) {
+	switch v.GetKind() {
+	case ValueKind_VALUE_KIND_INTEGER:
+		if v.GetPositiveValue() > math.MaxInt64 {
+			return math.MaxInt64, false, nil
+		}
+		return v.GetInt64(), true, nil
+	case ValueKind_VALUE_KIND_NUMBER:
+		if p == CoerceStrict {
+			break
+		}
+		return floatToInt64(v.GetFloat64())
+	case ValueKind_VALUE_KIND_STRING:
+		if p == CoerceStrict {
+			break
+		}
+		s := strings.TrimSpace(v.GetString())
+		if len(s) == 0 && p == CoerceFormLenient {
+			return 0, true, nil
+		}
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i, true, nil
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return floatToInt64(f)
+		}
+		return 0, false, fmt.Errorf("can not coerce %q to int64: invalid number", v.GetString())
+	case ValueKind_VALUE_KIND_BOOLEAN:
+		if p == CoerceFormLenient {
+			if v.GetBool() {
+				return 1, true, nil
+			}
+			return 0, true, nil
+		}
+	case ValueKind_VALUE_KIND_NULL, ValueKind_VALUE_KIND_UNSPECIFIED:
+		if p != CoerceStrict {
+			return 0, false, nil
+		}
+	}
+	return 0, false, p.errorf(v, "int64")
+}
+
+// ToUint64 converts v to an uint64, negative values are rejected.
+func (p CoercionPolicy) ToUint64(v *Value) (val uint64, lossless bool, err error) {
+	if v.GetKind() == ValueKind_VALUE_KIND_INTEGER && v.GetNegativeValue() == 0 {
+		return v.GetPositiveValue(), true, nil
+	}
+	i, lossless, err := p.ToInt64(v)
+	if err != nil {
+		return 0, false, err
+	}
+	if i < 0 {
+		return 0, false, fmt.Errorf("can not coerce negative %d to uint64", i)
+	}
+	return uint64(i), lossless, nil
+}
+
+// ToFloat64 converts v to a float64. Integers beyond ±2^53 are not lossless.
+func (p CoercionPolicy) ToFloat64(v *Value) (val float64, lossless bool, err error) {
+	switch v.GetKind() {
+	case ValueKind_VALUE_KIND_NUMBER:
+		return v.GetFloat64(), true, nil
+	case ValueKind_VALUE_KIND_INTEGER:
+		if v.GetNegativeValue() > 0 {
+			i := v.GetInt64()
+			return float64(i), i >= -1<<53, nil
+		}
+		u := v.GetPositiveValue()
+		return float64(u), u <= 1<<53, nil
+	case ValueKind_VALUE_KIND_STRING:
+		if p == CoerceStrict {
+			break
+		}
+		s := strings.TrimSpace(v.GetString())
+		if len(s) == 0 && p == CoerceFormLenient {
+			return 0, true, nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("can not coerce %q to float64: invalid number", v.GetString())
+		}
+		return f, true, nil
+	case ValueKind_VALUE_KIND_BOOLEAN:
+		if p == CoerceFormLenient {
+			if v.GetBool() {
+				return 1, true, nil
+			}
+			return 0, true, nil
+		}
+	case ValueKind_VALUE_KIND_NULL, ValueKind_VALUE_KIND_UNSPECIFIED:
+		if p != CoerceStrict {
+			return 0, false, nil
+		}
+	}
+	return 0, false, p.errorf(v, "float64")
+}
+
+// ToBool converts v to a bool.
+func (p CoercionPolicy) ToBool(v *Value) (val bool, lossless bool, err error) {
+	switch v.GetKind() {
+	case ValueKind_VALUE_KIND_BOOLEAN:
+		return v.GetBool(), true, nil
+	case ValueKind_VALUE_KIND_STRING:
+		s := strings.ToLower(strings.TrimSpace(v.GetString()))
+		switch {
+		case p == CoerceStrict:
+		case s == "true":
+			return true, true, nil
+		case s == "false":
+			return false, true, nil
+		case p != CoerceFormLenient:
+		case s == "1" || s == "on" || s == "yes" || s == "y":
+			return true, true, nil
+		case s == "0" || s == "off" || s == "no" || s == "n":
+			return false, true, nil
+		case s == "":
+			return false, false, nil
+		}
+	case ValueKind_VALUE_KIND_INTEGER, ValueKind_VALUE_KIND_NUMBER:
+		if p == CoerceFormLenient {
+			f, _, _ := p.ToFloat64(v)
+			return f != 0, f == 0 || f == 1, nil
+		}
+	case ValueKind_VALUE_KIND_NULL, ValueKind_VALUE_KIND_UNSPECIFIED:
+		if p != CoerceStrict {
+			return false, false, nil
+		}
+	}
+	return false, false, p.errorf(v, "bool")
+}
+
+// ToString converts v to a string, numbers and bools are formatted the way
+// they are written in JSON.
+func (p CoercionPolicy) ToString(v *Value) (val string, lossless bool, err error) {
+	switch v.GetKind() {
+	case ValueKind_VALUE_KIND_STRING:
+		return v.GetString(), true, nil
+	case ValueKind_VALUE_KIND_INTEGER:
+		if p == CoerceStrict {
+			break
+		}
+		if v.GetNegativeValue() > 0 {
+			return strconv.FormatInt(v.GetInt64(), 10), true, nil
+		}
+		return strconv.FormatUint(v.GetPositiveValue(), 10), true, nil
+	case ValueKind_VALUE_KIND_NUMBER:
+		if p == CoerceStrict {
+			break
+		}
+		return strconv.FormatFloat(v.GetFloat64(), 'g', -1, 64), true, nil
+	case ValueKind_VALUE_KIND_BOOLEAN:
+		if p == CoerceStrict {
+			break
+		}
+		return strconv.FormatBool(v.GetBool()), true, nil
+	case ValueKind_VALUE_KIND_NULL, ValueKind_VALUE_KIND_UNSPECIFIED:
+		if p != CoerceStrict {
+			return "", false, nil
+		}
+	}
+	return "", false, p.errorf(v, "string")
+}
+
+// ToTimestamp converts v to a Timestamp. Strings must be RFC3339 unless the
+// policy is form-lenient, which accepts every layout of Timestamp.Parse.
+func (p CoercionPolicy) ToTimestamp(v *Value) (val *Timestamp, lossless bool, err error) {
+	switch v.GetKind() {
+	case ValueKind_VALUE_KIND_STRING:
+		s := strings.TrimSpace(v.GetString())
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			return FromTime(t), true, nil
+		}
+		if p == CoerceFormLenient {
+			ts := &Timestamp{}
+			if err := ts.Parse(s); err == nil {
+				return ts, true, nil
+			}
+		}
+		return nil, false, fmt.Errorf("can not coerce %q to Timestamp under the %s policy", v.GetString(), p)
+	case ValueKind_VALUE_KIND_INTEGER, ValueKind_VALUE_KIND_NUMBER:
+		if p == CoerceStrict {
+			break
+		}
+		sec, _, _ := p.ToFloat64(v)
+		whole, frac := math.Modf(sec)
+		return &Timestamp{Seconds: int64(whole), Nanoseconds: int32(math.Round(frac * 1e9))}, true, nil
+	case ValueKind_VALUE_KIND_NULL, ValueKind_VALUE_KIND_UNSPECIFIED:
+		if p != CoerceStrict {
+			return nil, false, nil
+		}
+	}
+	return nil, false, p.errorf(v, "Timestamp")
+}
+
+// ToDuration converts v to a Duration. Strings use the time.ParseDuration
+// format, numbers are seconds, as in the JSON encoding of Duration.
+func (p CoercionPolicy) ToDuration(v *Value) (val *Duration, lossless bool, err error) {
+	switch v.GetKind() {
+	case ValueKind_VALUE_KIND_STRING:
+		s := strings.TrimSpace(v.GetString())
+		if d, err := ParseDuration(s); err == nil {
+			return d, true, nil
+		}
+		if p != CoerceStrict {
+			if sec, err := strconv.ParseFloat(s, 64); err == nil {
+				return NewDuration(sec), true, nil
+			}
+		}
+		return nil, false, fmt.Errorf("can not coerce %q to Duration under the %s policy", v.GetString(), p)
+	case ValueKind_VALUE_KIND_INTEGER, ValueKind_VALUE_KIND_NUMBER:
+		if p == CoerceStrict {
+			break
+		}
+		sec, lossless, _ := p.ToFloat64(v)
+		return NewDuration(sec), lossless, nil
+	case ValueKind_VALUE_KIND_NULL, ValueKind_VALUE_KIND_UNSPECIFIED:
+		if p != CoerceStrict {
+			return nil, false, nil
+		}
+	}
+	return nil, false, p.errorf(v, "Duration")
+}
+
+func floatToInt64(f float64) (int64, bool, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false, fmt.Errorf("can not coerce %v to int64", f)
+	}
+	if f >= math.MaxInt64 {
+		return math.MaxInt64, false, nil
+	}
+	if f <= math.MinInt64 {
+		return math.MinInt64, f == math.MinInt64, nil
+	}
+	i := int64(f)
+	return i, float64(i) == f, nil
+}
+
+// ObjectCoercer reads the fields of an Object through a CoercionPolicy.
+type ObjectCoercer struct {
+	obj    *Object
+	policy CoercionPolicy
+}
+
+// Coerce returns typed getters of x which convert values by the given policy
+// and report failed conversions as errors, instead of silently returning the
+// zero value as the plain getters of Object do.
+func (x *Object) Coerce(policy CoercionPolicy) *ObjectCoercer {
+	return &ObjectCoercer{obj: x, policy: policy}
+}
+
+func (c *ObjectCoercer) value(key string) (*Value, error) {
+	v := c.obj.GetValue(key)
+	if v == nil {
+		return nil, fmt.Errorf("key %q not found", key)
+	}
+	return v, nil
+}
+
+func (c *ObjectCoercer) GetBool(key string) (bool, error) {
+	v, err := c.value(key)
+	if err != nil {
+		return false, err
+	}
+	val, _, err := c.policy.ToBool(v)
+	return val, err
+}
+
+func (c *ObjectCoercer) GetInt(key string) (int, error) {
+	val, err := c.GetInt64(key)
+	return int(val), err
+}
+
+func (c *ObjectCoercer) GetInt64(key string) (int64, error) {
+	v, err := c.value(key)
+	if err != nil {
+		return 0, err
+	}
+	val, _, err := c.policy.ToInt64(v)
+	return val, err
+}
+
+func (c *ObjectCoercer) GetUint64(key string) (uint64, error) {
+	v, err := c.value(key)
+	if err != nil {
+		return 0, err
+	}
+	val, _, err := c.policy.ToUint64(v)
+	return val, err
+}
+
+func (c *ObjectCoercer) GetFloat64(key string) (float64, error) {
+	v, err := c.value(key)
+	if err != nil {
+		return 0, err
+	}
+	val, _, err := c.policy.ToFloat64(v)
+	return val, err
+}
+
+func (c *ObjectCoercer) GetString(key string) (string, error) {
+	v, err := c.value(key)
+	if err != nil {
+		return "", err
+	}
+	val, _, err := c.policy.ToString(v)
+	return val, err
+}
+
+func (c *ObjectCoercer) GetTimestamp(key string) (*Timestamp, error) {
+	v, err := c.value(key)
+	if err != nil {
+		return nil, err
+	}
+	val, _, err := c.policy.ToTimestamp(v)
+	return val, err
+}
+
+func (c *ObjectCoercer) GetDuration(key string) (*Duration, error) {
+	v, err := c.value(key)
+	if err != nil {
+		return nil, err
+	}
+	val, _, err := c.policy.ToDuration(v)
+	return val, err
+}
+
+// UnmarshalWith decodes the query parameter of name into value, which must be
+// a non-nil pointer, converting its text by the given policy; with the strict
+// policy only strings, timestamps and durations can be decoded. Slices are
+// filled from repeated parameters, or from a single comma separated one
+// unless their elements are strings, so that `?tag=a,b` keeps its comma.
+// Other types are decoded the way Unmarshal does.
+func (x *Url_Query) UnmarshalWith(name string, value interface{}, policy CoercionPolicy) error {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("Url_Query.UnmarshalWith: expected a non-nil pointer, got %T", value)
+	}
+	if x == nil || x.Vals == nil {
+		return nil
+	}
+	param, ok := x.Vals[name]
+	if !ok || param == nil || len(param.Vals) == 0 {
+		return nil
+	}
+
+	v := rv.Elem()
+	if v.Kind() == reflect.Slice && isCoercible(v.Type().Elem()) {
+		vals := param.Vals
+		if len(vals) == 1 && !isStringKind(v.Type().Elem()) {
+			vals = strings.Split(vals[0], ",")
+		}
+		slice := reflect.MakeSlice(v.Type(), len(vals), len(vals))
+		for i, val := range vals {
+			if err := coerceInto(slice.Index(i), val, policy); err != nil {
+				return fmt.Errorf("query parameter %s[%d]: %w", name, i, err)
+			}
+		}
+		v.Set(slice)
+		return nil
+	}
+	if isCoercible(v.Type()) {
+		if err := coerceInto(v, param.Vals[0], policy); err != nil {
+			return fmt.Errorf("query parameter %s: %w", name, err)
+		}
+		return nil
+	}
+	return x.Unmarshal(name, value)
+}
+
+var (
+	timestampType = reflect.TypeOf(Timestamp{})
+	durationType  = reflect.TypeOf(Duration{})
+)
+
+func isCoercible(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return t == timestampType || t == durationType
+}
+
+func isStringKind(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.String
+}
+
+func coerceInto(v reflect.Value, str string, policy CoercionPolicy) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	val := NewStringValue(str)
+	switch {
+	case v.Type() == timestampType:
+		ts, _, err := policy.ToTimestamp(val)
+		if err != nil {
+			return err
+		}
+		v.FieldByName("Seconds").SetInt(ts.Seconds)
+		v.FieldByName("Nanoseconds").SetInt(int64(ts.Nanoseconds))
+		return nil
+	case v.Type() == durationType:
+		d, _, err := policy.ToDuration(val)
+		if err != nil {
+			return err
+		}
+		v.FieldByName("Seconds").SetInt(d.Seconds)
+		v.FieldByName("Nanoseconds").SetInt(int64(d.Nanoseconds))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		b, _, err := policy.ToBool(val)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.String:
+		v.SetString(str)
+	case reflect.Float32, reflect.Float64:
+		f, _, err := policy.ToFloat64(val)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, _, err := policy.ToInt64(val)
+		if err != nil {
+			return err
+		}
+		if v.OverflowInt(i) {
+			return fmt.Errorf("%d overflows %s", i, v.Type())
+		}
+		v.SetInt(i)
+	default:
+		u, _, err := policy.ToUint64(val)
+		if err != nil {
+			return err
+		}
+		if v.OverflowUint(u) {
+			return fmt.Errorf("%d overflows %s", u, v.Type())
+		}
+		v.SetUint(u)
+	}
+	return nil
+}
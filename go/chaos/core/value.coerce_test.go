@@ -0,0 +1,256 @@
+package core
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoercionPolicy_ToInt64(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   CoercionPolicy
+		val      *Value
+		want     int64
+		lossless bool
+		wantErr  bool
+	}{
+		{name: "strict int", policy: CoerceStrict, val: NewIntValue(-3), want: -3, lossless: true},
+		{name: "strict number", policy: CoerceStrict, val: NewFloat64Value(3), wantErr: true},
+		{name: "strict string", policy: CoerceStrict, val: NewStringValue("3"), wantErr: true},
+		{name: "json integral number", policy: CoerceJSONLenient, val: NewFloat64Value(3), want: 3, lossless: true},
+		{name: "json fractional number", policy: CoerceJSONLenient, val: NewFloat64Value(3.7), want: 3},
+		{name: "json string", policy: CoerceJSONLenient, val: NewStringValue(" 42 "), want: 42, lossless: true},
+		{name: "json float string", policy: CoerceJSONLenient, val: NewStringValue("1.5"), want: 1},
+		{name: "json invalid string", policy: CoerceJSONLenient, val: NewStringValue("abc"), wantErr: true},
+		{name: "json empty string", policy: CoerceJSONLenient, val: NewStringValue(""), wantErr: true},
+		{name: "json bool", policy: CoerceJSONLenient, val: NewBoolValue(true), wantErr: true},
+		{name: "json null", policy: CoerceJSONLenient, val: NewNullValue(), want: 0},
+		{name: "form empty string", policy: CoerceFormLenient, val: NewStringValue(""), want: 0, lossless: true},
+		{name: "form bool", policy: CoerceFormLenient, val: NewBoolValue(true), want: 1, lossless: true},
+		{name: "overflow", policy: CoerceJSONLenient, val: NewFloat64Value(1e20), want: math.MaxInt64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, lossless, err := tt.policy.ToInt64(tt.val)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.lossless, lossless)
+		})
+	}
+}
+
+func TestCoercionPolicy_ToUint64(t *testing.T) {
+	got, lossless, err := CoerceStrict.ToUint64(NewUint64Value(math.MaxUint64))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(math.MaxUint64), got)
+	assert.True(t, lossless)
+
+	_, _, err = CoerceJSONLenient.ToUint64(NewStringValue("-1"))
+	assert.Error(t, err)
+}
+
+func TestCoercionPolicy_ToFloat64(t *testing.T) {
+	f, lossless, err := CoerceStrict.ToFloat64(NewIntValue(7))
+	assert.NoError(t, err)
+	assert.Equal(t, 7.0, f)
+	assert.True(t, lossless)
+
+	_, lossless, err = CoerceStrict.ToFloat64(NewInt64Value(1<<53 + 1))
+	assert.NoError(t, err)
+	assert.False(t, lossless)
+
+	_, _, err = CoerceStrict.ToFloat64(NewStringValue("1.5"))
+	assert.Error(t, err)
+
+	f, lossless, err = CoerceJSONLenient.ToFloat64(NewStringValue("1.5"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, f)
+	assert.True(t, lossless)
+}
+
+func TestCoercionPolicy_ToBool(t *testing.T) {
+	tests := []struct {
+		policy   CoercionPolicy
+		val      *Value
+		want     bool
+		lossless bool
+		wantErr  bool
+	}{
+		{policy: CoerceStrict, val: NewBoolValue(true), want: true, lossless: true},
+		{policy: CoerceStrict, val: NewStringValue("true"), wantErr: true},
+		{policy: CoerceJSONLenient, val: NewStringValue("True"), want: true, lossless: true},
+		{policy: CoerceJSONLenient, val: NewStringValue("on"), wantErr: true},
+		{policy: CoerceJSONLenient, val: NewIntValue(1), wantErr: true},
+		{policy: CoerceFormLenient, val: NewStringValue("on"), want: true, lossless: true},
+		{policy: CoerceFormLenient, val: NewStringValue("0"), want: false, lossless: true},
+		{policy: CoerceFormLenient, val: NewStringValue(""), want: false},
+		{policy: CoerceFormLenient, val: NewStringValue("maybe"), wantErr: true},
+		{policy: CoerceFormLenient, val: NewIntValue(1), want: true, lossless: true},
+		{policy: CoerceFormLenient, val: NewFloat64Value(2.5), want: true},
+	}
+	for _, tt := range tests {
+		got, lossless, err := tt.policy.ToBool(tt.val)
+		if tt.wantErr {
+			assert.Error(t, err, "%s %v", tt.policy, tt.val)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got, "%s %v", tt.policy, tt.val)
+		assert.Equal(t, tt.lossless, lossless, "%s %v", tt.policy, tt.val)
+	}
+}
+
+func TestCoercionPolicy_ToString(t *testing.T) {
+	_, _, err := CoerceStrict.ToString(NewIntValue(1))
+	assert.Error(t, err)
+
+	s, lossless, err := CoerceJSONLenient.ToString(NewFloat64Value(1.25))
+	assert.NoError(t, err)
+	assert.Equal(t, "1.25", s)
+	assert.True(t, lossless)
+
+	s, _, err = CoerceJSONLenient.ToString(NewIntValue(-12))
+	assert.NoError(t, err)
+	assert.Equal(t, "-12", s)
+
+	s, _, err = CoerceJSONLenient.ToString(NewBoolValue(false))
+	assert.NoError(t, err)
+	assert.Equal(t, "false", s)
+}
+
+func TestCoercionPolicy_ToTimestamp(t *testing.T) {
+	ts, lossless, err := CoerceStrict.ToTimestamp(NewStringValue("2024-05-01T08:00:00.5Z"))
+	assert.NoError(t, err)
+	assert.True(t, lossless)
+	assert.Equal(t, time.Date(2024, 5, 1, 8, 0, 0, 5e8, time.UTC), ts.ToTime().UTC())
+
+	_, _, err = CoerceStrict.ToTimestamp(NewIntValue(1714550400))
+	assert.Error(t, err)
+	_, _, err = CoerceJSONLenient.ToTimestamp(NewStringValue("2024-05-01 08:00:00"))
+	assert.Error(t, err)
+
+	ts, _, err = CoerceJSONLenient.ToTimestamp(NewIntValue(1714550400))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1714550400), ts.Seconds)
+
+	ts, _, err = CoerceFormLenient.ToTimestamp(NewStringValue("2024-05-01 08:00:00"))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC), ts.ToTime().UTC())
+}
+
+func TestCoercionPolicy_ToDuration(t *testing.T) {
+	d, lossless, err := CoerceStrict.ToDuration(NewStringValue("1m30s"))
+	assert.NoError(t, err)
+	assert.True(t, lossless)
+	assert.Equal(t, 90*time.Second, d.ToDuration())
+
+	_, _, err = CoerceStrict.ToDuration(NewStringValue("90"))
+	assert.Error(t, err)
+
+	d, _, err = CoerceJSONLenient.ToDuration(NewStringValue("90"))
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Second, d.ToDuration())
+
+	d, _, err = CoerceJSONLenient.ToDuration(NewFloat64Value(1.5))
+	assert.NoError(t, err)
+	assert.Equal(t, 1500*time.Millisecond, d.ToDuration())
+}
+
+func TestObject_Coerce(t *testing.T) {
+	obj := NewObject().
+		SetString("count", "12").
+		SetString("enabled", "yes").
+		SetInt("ratio", 2).
+		SetString("at", "2024-05-01T08:00:00Z").
+		SetString("timeout", "5s")
+
+	count, err := obj.Coerce(CoerceJSONLenient).GetInt("count")
+	assert.NoError(t, err)
+	assert.Equal(t, 12, count)
+
+	_, err = obj.Coerce(CoerceStrict).GetInt("count")
+	assert.Error(t, err)
+
+	_, err = obj.Coerce(CoerceJSONLenient).GetBool("enabled")
+	assert.Error(t, err)
+	enabled, err := obj.Coerce(CoerceFormLenient).GetBool("enabled")
+	assert.NoError(t, err)
+	assert.True(t, enabled)
+
+	ratio, err := obj.Coerce(CoerceStrict).GetFloat64("ratio")
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, ratio)
+
+	at, err := obj.Coerce(CoerceStrict).GetTimestamp("at")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1714550400), at.Seconds)
+
+	timeout, err := obj.Coerce(CoerceStrict).GetDuration("timeout")
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Second, timeout.ToDuration())
+
+	_, err = obj.Coerce(CoerceJSONLenient).GetString("missing")
+	assert.Error(t, err)
+}
+
+func TestUrl_Query_UnmarshalWith(t *testing.T) {
+	query := &Url_Query{Vals: map[string]*StringValues{
+		"page":    {Vals: []string{"2"}},
+		"debug":   {Vals: []string{"on"}},
+		"ids":     {Vals: []string{"1", "2", "3"}},
+		"codes":   {Vals: []string{"4,5"}},
+		"tags":    {Vals: []string{"a,b"}},
+		"since":   {Vals: []string{"2024-05-01 08:00:00"}},
+		"timeout": {Vals: []string{"30"}},
+		"small":   {Vals: []string{"300"}},
+	}}
+
+	var page int32
+	assert.NoError(t, query.UnmarshalWith("page", &page, CoerceJSONLenient))
+	assert.Equal(t, int32(2), page)
+	assert.Error(t, query.UnmarshalWith("page", &page, CoerceStrict))
+
+	var debug bool
+	assert.Error(t, query.UnmarshalWith("debug", &debug, CoerceJSONLenient))
+	assert.NoError(t, query.UnmarshalWith("debug", &debug, CoerceFormLenient))
+	assert.True(t, debug)
+
+	var ids []uint64
+	assert.NoError(t, query.UnmarshalWith("ids", &ids, CoerceFormLenient))
+	assert.Equal(t, []uint64{1, 2, 3}, ids)
+
+	var codes []int
+	assert.NoError(t, query.UnmarshalWith("codes", &codes, CoerceFormLenient))
+	assert.Equal(t, []int{4, 5}, codes)
+
+	var tags []string
+	assert.NoError(t, query.UnmarshalWith("tags", &tags, CoerceStrict))
+	assert.Equal(t, []string{"a,b"}, tags)
+
+	var since *Timestamp
+	assert.Error(t, query.UnmarshalWith("since", &since, CoerceJSONLenient))
+	assert.NoError(t, query.UnmarshalWith("since", &since, CoerceFormLenient))
+	assert.Equal(t, int64(1714550400), since.Seconds)
+
+	timeout := &Duration{}
+	assert.NoError(t, query.UnmarshalWith("timeout", timeout, CoerceFormLenient))
+	assert.Equal(t, 30*time.Second, timeout.ToDuration())
+
+	var small int8
+	assert.Error(t, query.UnmarshalWith("small", &small, CoerceFormLenient))
+
+	var missing int
+	assert.NoError(t, query.UnmarshalWith("missing", &missing, CoerceStrict))
+	assert.Equal(t, 0, missing)
+
+	assert.Error(t, query.UnmarshalWith("page", page, CoerceJSONLenient))
+	assert.Error(t, query.UnmarshalWith("page", nil, CoerceJSONLenient))
+	assert.Error(t, query.UnmarshalWith("page", (*int)(nil), CoerceJSONLenient))
+}
@@ -1,7 +1,6 @@
 package core
 
 import (
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -26,7 +25,7 @@ const ValueTypeFullName = "core.Value"
 //	║ float32, float64                      │ stored as NumberValue                      ║
 //	║ json.Number                           │ stored as NumberValue                      ║
 //	║ string                                │ stored as StringValue; must be valid UTF-8 ║
-//	║ []byte                                │ stored as StringValue; base64-encoded      ║
+//	║ []byte                                │ stored as BytesValue                       ║
 //	║ map[string]any                        │ stored as ObjectValue                      ║
 //	║ []any                                 │ stored as ValuesValue                      ║
 //	╚═══════════════════════════════════════╧════════════════════════════════════════════╝
@@ -75,9 +74,7 @@ func NewValue(val any) (*Value, error) {
 		}
 		return NewStringValue(v), nil
 	case []byte:
-		s := base64.StdEncoding.EncodeToString(v)
-		return NewStringValue(s), nil
-		// return NewBytesValue(v), nil
+		return NewBytesValue(v), nil
 	case map[string]any:
 		v2, err := NewObjectFromMap(v)
 		if err != nil {
@@ -241,29 +238,29 @@ func NewArrayValue(vals ...*Value) *Value {
 }
 
 func NewIntArrayValue(vals ...int) *Value {
-	_vals := make([]*Value, 0, len(vals))
+	packed := make([]int64, 0, len(vals))
 	for _, v := range vals {
-		_vals = append(_vals, NewIntValue(v))
+		packed = append(packed, int64(v))
 	}
-	return &Value{Val: &Value_ValuesValue{ValuesValue: &Values{Vals: _vals}}}
+	return NewPackedInt64ArrayValue(packed...)
 }
 
 func NewInt32ArrayValue(vals ...int32) *Value {
-	_vals := make([]*Value, 0, len(vals))
+	packed := make([]int64, 0, len(vals))
 	for _, v := range vals {
-		_vals = append(_vals, NewInt32Value(v))
+		packed = append(packed, int64(v))
 	}
-	return &Value{Val: &Value_ValuesValue{ValuesValue: &Values{Vals: _vals}}}
+	return NewPackedInt64ArrayValue(packed...)
 }
 
+// NewInt64ArrayValue returns a packed array of a copy of vals, see
+// NewPackedInt64ArrayValue.
 func NewInt64ArrayValue(vals ...int64) *Value {
-	_vals := make([]*Value, 0, len(vals))
-	for _, v := range vals {
-		_vals = append(_vals, NewInt64Value(v))
-	}
-	return &Value{Val: &Value_ValuesValue{ValuesValue: &Values{Vals: _vals}}}
+	return NewPackedInt64ArrayValue(append([]int64{}, vals...)...)
 }
 
+// NewUintArrayValue returns an array of boxed Values, as values above
+// math.MaxInt64 do not fit into a packed int64 array.
 func NewUintArrayValue(vals ...uint) *Value {
 	_vals := make([]*Value, 0, len(vals))
 	for _, v := range vals {
@@ -273,13 +270,15 @@ func NewUintArrayValue(vals ...uint) *Value {
 }
 
 func NewUint32ArrayValue(vals ...uint32) *Value {
-	_vals := make([]*Value, 0, len(vals))
+	packed := make([]int64, 0, len(vals))
 	for _, v := range vals {
-		_vals = append(_vals, NewUint32Value(v))
+		packed = append(packed, int64(v))
 	}
-	return &Value{Val: &Value_ValuesValue{ValuesValue: &Values{Vals: _vals}}}
+	return NewPackedInt64ArrayValue(packed...)
 }
 
+// NewUint64ArrayValue returns an array of boxed Values, as values above
+// math.MaxInt64 do not fit into a packed int64 array.
 func NewUint64ArrayValue(vals ...uint64) *Value {
 	_vals := make([]*Value, 0, len(vals))
 	for _, v := range vals {
@@ -289,27 +288,23 @@ func NewUint64ArrayValue(vals ...uint64) *Value {
 }
 
 func NewFloat32ArrayValue(vals ...float32) *Value {
-	_vals := make([]*Value, 0, len(vals))
+	packed := make([]float64, 0, len(vals))
 	for _, v := range vals {
-		_vals = append(_vals, NewFloat32Value(v))
+		packed = append(packed, float64(v))
 	}
-	return &Value{Val: &Value_ValuesValue{ValuesValue: &Values{Vals: _vals}}}
+	return NewPackedFloat64ArrayValue(packed...)
 }
 
+// NewFloat64ArrayValue returns a packed array of a copy of vals, see
+// NewPackedFloat64ArrayValue.
 func NewFloat64ArrayValue(vals ...float64) *Value {
-	_vals := make([]*Value, 0, len(vals))
-	for _, v := range vals {
-		_vals = append(_vals, NewFloat64Value(v))
-	}
-	return &Value{Val: &Value_ValuesValue{ValuesValue: &Values{Vals: _vals}}}
+	return NewPackedFloat64ArrayValue(append([]float64{}, vals...)...)
 }
 
+// NewStringArrayValue returns a packed array of a copy of vals, see
+// NewPackedStringArrayValue.
 func NewStringArrayValue(vals ...string) *Value {
-	_vals := make([]*Value, 0, len(vals))
-	for _, v := range vals {
-		_vals = append(_vals, NewStringValue(v))
-	}
-	return &Value{Val: &Value_ValuesValue{ValuesValue: &Values{Vals: _vals}}}
+	return NewPackedStringArrayValue(append([]string{}, vals...)...)
 }
 
 func NewObjectArrayValue(vals ...*Object) *Value {
@@ -320,13 +315,39 @@ func NewObjectArrayValue(vals ...*Object) *Value {
 	return &Value{Val: &Value_ValuesValue{ValuesValue: &Values{Vals: _vals}}}
 }
 
+// NewPackedBoolArrayValue returns a packed array holding vals without copying
+// them, which avoids the per-element boxing of NewArrayValue.
+func NewPackedBoolArrayValue(vals ...bool) *Value {
+	return &Value{Val: &Value_BoolValues{BoolValues: &BoolValues{Vals: vals}}}
+}
+
+// NewPackedInt64ArrayValue returns a packed array holding vals without copying
+// them.
+func NewPackedInt64ArrayValue(vals ...int64) *Value {
+	return &Value{Val: &Value_Int64Values{Int64Values: &Int64Values{Vals: vals}}}
+}
+
+// NewPackedFloat64ArrayValue returns a packed array holding vals without
+// copying them.
+func NewPackedFloat64ArrayValue(vals ...float64) *Value {
+	return &Value{Val: &Value_Float64Values{Float64Values: &Float64Values{Vals: vals}}}
+}
+
+// NewPackedStringArrayValue returns a packed array holding vals without
+// copying them.
+func NewPackedStringArrayValue(vals ...string) *Value {
+	return &Value{Val: &Value_StringValues{StringValues: &StringValues{Vals: vals}}}
+}
+
 // AsInterface converts x to a general-purpose Go interface.
 //
 // Calling Value.MarshalJSON and "encoding/json".Marshal on this output produce
 // semantically equivalent JSON (assuming no errors occur).
 //
 // Floating-point values (i.e., "NaN", "Infinity", and "-Infinity") are
-// converted as strings to remain compatible with MarshalJSON.
+// converted as strings to remain compatible with MarshalJSON. Bytes are
+// returned as []byte, which "encoding/json" encodes as plain base64 instead
+// of the forms of BytesJSONMode.
 func (x *Value) AsInterface() any {
 	switch v := x.GetVal().(type) {
 	case *Value_NullValue:
@@ -353,10 +374,14 @@ func (x *Value) AsInterface() any {
 		}
 	case *Value_StringValue:
 		return v.StringValue
+	case *Value_BytesValue:
+		return v.BytesValue
 	case *Value_ObjectValue:
 		return v.ObjectValue.AsMap()
 	case *Value_ValuesValue:
 		return v.ValuesValue.AsSlice()
+	case *Value_BoolValues, *Value_Int64Values, *Value_Float64Values, *Value_StringValues:
+		return (&Values{Vals: x.GetValues()}).AsSlice()
 	default:
 		return v
 	}
@@ -379,7 +404,7 @@ func (x *Value) GetKind() ValueKind {
 			return ValueKind_VALUE_KIND_BYTES
 		case *Value_ObjectValue:
 			return ValueKind_VALUE_KIND_OBJECT
-		case *Value_ValuesValue:
+		case *Value_ValuesValue, *Value_BoolValues, *Value_Int64Values, *Value_Float64Values, *Value_StringValues:
 			return ValueKind_VALUE_KIND_ARRAY
 		}
 	}
@@ -444,14 +469,57 @@ func (x *Value) GetObject() *Object {
 	return x.GetObjectValue()
 }
 
+// IsPackedArray reports whether x is one of the packed arrays.
+func (x *Value) IsPackedArray() bool {
+	switch x.GetVal().(type) {
+	case *Value_BoolValues, *Value_Int64Values, *Value_Float64Values, *Value_StringValues:
+		return true
+	}
+	return false
+}
+
+// GetValues returns the elements of an array. The elements of packed arrays
+// are boxed into a new slice of new Values on every call, so changes to the
+// slice or its Values are not written back to x; use the typed accessors like
+// GetInt64Array, or replace the array, to change them.
 func (x *Value) GetValues() []*Value {
-	if vals := x.GetValuesValue(); vals != nil {
-		return vals.Vals
+	switch v := x.GetVal().(type) {
+	case *Value_ValuesValue:
+		if v.ValuesValue != nil {
+			return v.ValuesValue.Vals
+		}
+	case *Value_BoolValues:
+		vals := make([]*Value, 0, len(v.BoolValues.GetVals()))
+		for _, val := range v.BoolValues.GetVals() {
+			vals = append(vals, NewBoolValue(val))
+		}
+		return vals
+	case *Value_Int64Values:
+		vals := make([]*Value, 0, len(v.Int64Values.GetVals()))
+		for _, val := range v.Int64Values.GetVals() {
+			vals = append(vals, NewInt64Value(val))
+		}
+		return vals
+	case *Value_Float64Values:
+		vals := make([]*Value, 0, len(v.Float64Values.GetVals()))
+		for _, val := range v.Float64Values.GetVals() {
+			vals = append(vals, NewFloat64Value(val))
+		}
+		return vals
+	case *Value_StringValues:
+		vals := make([]*Value, 0, len(v.StringValues.GetVals()))
+		for _, val := range v.StringValues.GetVals() {
+			vals = append(vals, NewStringValue(val))
+		}
+		return vals
 	}
 	return nil
 }
 
 func (x *Value) GetBoolArray() []bool {
+	if packed := x.GetBoolValues(); packed != nil {
+		return packed.Vals
+	}
 	vals := x.GetValues()
 	array := make([]bool, 0, len(vals))
 	for _, v := range vals {
@@ -461,6 +529,13 @@ func (x *Value) GetBoolArray() []bool {
 }
 
 func (x *Value) GetIntArray() []int {
+	if packed := x.GetInt64Values(); packed != nil {
+		array := make([]int, 0, len(packed.Vals))
+		for _, v := range packed.Vals {
+			array = append(array, int(v))
+		}
+		return array
+	}
 	vals := x.GetValues()
 	array := make([]int, 0, len(vals))
 	for _, v := range vals {
@@ -469,7 +544,12 @@ func (x *Value) GetIntArray() []int {
 	return array
 }
 
+// GetInt64Array returns the elements of an array as int64s. Packed int64
+// arrays are returned without copying, so the result must not be modified.
 func (x *Value) GetInt64Array() []int64 {
+	if packed := x.GetInt64Values(); packed != nil {
+		return packed.Vals
+	}
 	vals := x.GetValues()
 	array := make([]int64, 0, len(vals))
 	for _, v := range vals {
@@ -479,6 +559,16 @@ func (x *Value) GetInt64Array() []int64 {
 }
 
 func (x *Value) GetUintArray() []uint {
+	if packed := x.GetInt64Values(); packed != nil {
+		array := make([]uint, 0, len(packed.Vals))
+		for _, v := range packed.Vals {
+			if v < 0 {
+				v = 0
+			}
+			array = append(array, uint(v))
+		}
+		return array
+	}
 	vals := x.GetValues()
 	array := make([]uint, 0, len(vals))
 	for _, v := range vals {
@@ -487,7 +577,20 @@ func (x *Value) GetUintArray() []uint {
 	return array
 }
 
+// GetFloat64Array returns the elements of an array as float64s. Packed
+// float64 arrays are returned without copying, so the result must not be
+// modified.
 func (x *Value) GetFloat64Array() []float64 {
+	if packed := x.GetFloat64Values(); packed != nil {
+		return packed.Vals
+	}
+	if packed := x.GetInt64Values(); packed != nil {
+		array := make([]float64, 0, len(packed.Vals))
+		for _, v := range packed.Vals {
+			array = append(array, float64(v))
+		}
+		return array
+	}
 	vals := x.GetValues()
 	array := make([]float64, 0, len(vals))
 	for _, v := range vals {
@@ -497,6 +600,9 @@ func (x *Value) GetFloat64Array() []float64 {
 }
 
 func (x *Value) GetStringArray() []string {
+	if packed := x.GetStringValues(); packed != nil {
+		return packed.Vals
+	}
 	vals := x.GetValues()
 	array := make([]string, 0, len(vals))
 	for _, v := range vals {
@@ -506,10 +612,7 @@ func (x *Value) GetStringArray() []string {
 }
 
 func (x *Value) GetValueArray() []*Value {
-	if values := x.GetValuesValue(); values != nil {
-		return values.Vals
-	}
-	return nil
+	return x.GetValues()
 }
 
 func (x *Value) GetObjectArray() []*Object {
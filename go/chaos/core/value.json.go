@@ -4,7 +4,9 @@ import (
 	"encoding/base64"
 	"errors"
 	"math"
+	"sort"
 	"strings"
+	"sync"
 	"unsafe"
 
 	jsoniter "github.com/json-iterator/go"
@@ -42,12 +44,11 @@ func (codec *ValueCodec) DecodeAny(a jsoniter.Any) (*Value, error) {
 		return NewInt64Value(intVal), nil
 	case jsoniter.StringValue:
 		str := a.ToString()
-		if strings.HasPrefix(str, Base64Prefix) {
-			ds, err := base64.StdEncoding.DecodeString(str[len(Base64Prefix):])
+		if bs, ok, err := decodeBytesString(str); ok {
 			if err != nil {
 				return nil, err
 			}
-			return NewBytesValue(ds), nil
+			return NewBytesValue(bs), nil
 		}
 
 		switch str {
@@ -61,6 +62,12 @@ func (codec *ValueCodec) DecodeAny(a jsoniter.Any) (*Value, error) {
 			return NewStringValue(str), nil
 		}
 	case jsoniter.ObjectValue:
+		if bs, ok, err := decodeBytesWrapper(a); ok {
+			if err != nil {
+				return nil, err
+			}
+			return NewBytesValue(bs), nil
+		}
 		val := make(map[string]*Value)
 		a.ToVal(&val)
 		return NewMapValue(val), nil
@@ -75,7 +82,11 @@ func (codec *ValueCodec) DecodeAny(a jsoniter.Any) (*Value, error) {
 
 func (codec *ValueCodec) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
 	a := iter.ReadAny()
-	v, _ := codec.DecodeAny(a)
+	v, err := codec.DecodeAny(a)
+	if err != nil {
+		iter.ReportError("ValueCodec.Decode", err.Error())
+		return
+	}
 	(*Value)(ptr).Val = v.Val
 }
 
@@ -85,25 +96,202 @@ func (codec *ValueCodec) IsEmpty(ptr unsafe.Pointer) bool {
 }
 
 func (codec *ValueCodec) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
-	val := (*Value)(ptr)
-	switch v := val.Val.(type) {
+	writeValue(stream, (*Value)(ptr))
+}
+
+// MarshalJSON encodes x with ValueCodec, so "encoding/json" produces the same
+// JSON as jsoniter.
+func (x *Value) MarshalJSON() ([]byte, error) {
+	return jsoniter.Marshal(x)
+}
+
+func (x *Value) UnmarshalJSON(data []byte) error {
+	return jsoniter.Unmarshal(data, x)
+}
+
+var (
+	keysPool  = sync.Pool{New: func() any { keys := make([]string, 0, 16); return &keys }}
+	bytesPool = sync.Pool{New: func() any { buf := make([]byte, 0, 256); return &buf }}
+)
+
+// writeValue encodes v by walking its oneof directly, without reflection nor
+// intermediate Go values. Object keys are written in sorted order, which is
+// what the map encoders of jsoniter produce with SortMapKeys, and is one of
+// the orders they produce without.
+func writeValue(stream *jsoniter.Stream, v *Value) {
+	if v == nil {
+		stream.WriteNil()
+		return
+	}
+
+	switch val := v.Val.(type) {
 	case *Value_BoolValue:
-		stream.WriteBool(v.BoolValue)
+		stream.WriteBool(val.BoolValue)
 	case *Value_PositiveValue:
-		stream.WriteUint64(v.PositiveValue)
+		stream.WriteUint64(val.PositiveValue)
 	case *Value_NegativeValue:
-		stream.WriteInt64(val.GetInt64())
+		stream.WriteInt64(v.GetInt64())
 	case *Value_NumberValue:
-		stream.WriteFloat64Lossy(v.NumberValue)
+		stream.WriteFloat64Lossy(val.NumberValue)
 	case *Value_StringValue:
-		stream.WriteString(v.StringValue)
+		stream.WriteString(val.StringValue)
 	case *Value_BytesValue:
-		stream.WriteString(Base64Prefix + base64.StdEncoding.EncodeToString(v.BytesValue))
+		writeBytes(stream, val.BytesValue)
 	case *Value_ValuesValue:
-		stream.WriteVal(v.ValuesValue.Vals)
+		writeValues(stream, val.ValuesValue.GetVals())
 	case *Value_ObjectValue:
-		stream.WriteVal(v.ObjectValue.Vals)
+		writeObject(stream, val.ObjectValue.GetVals())
+	case *Value_BoolValues:
+		stream.WriteArrayStart()
+		for i, b := range val.BoolValues.GetVals() {
+			if i > 0 {
+				stream.WriteMore()
+			}
+			stream.WriteBool(b)
+		}
+		stream.WriteArrayEnd()
+	case *Value_Int64Values:
+		stream.WriteArrayStart()
+		for i, n := range val.Int64Values.GetVals() {
+			if i > 0 {
+				stream.WriteMore()
+			}
+			stream.WriteInt64(n)
+		}
+		stream.WriteArrayEnd()
+	case *Value_Float64Values:
+		stream.WriteArrayStart()
+		for i, f := range val.Float64Values.GetVals() {
+			if i > 0 {
+				stream.WriteMore()
+			}
+			stream.WriteFloat64Lossy(f)
+		}
+		stream.WriteArrayEnd()
+	case *Value_StringValues:
+		stream.WriteArrayStart()
+		for i, str := range val.StringValues.GetVals() {
+			if i > 0 {
+				stream.WriteMore()
+			}
+			stream.WriteString(str)
+		}
+		stream.WriteArrayEnd()
 	default:
 		stream.WriteNil()
 	}
 }
+
+func writeValues(stream *jsoniter.Stream, vals []*Value) {
+	if vals == nil {
+		stream.WriteNil()
+		return
+	}
+	stream.WriteArrayStart()
+	for i, v := range vals {
+		if i > 0 {
+			stream.WriteMore()
+		}
+		writeValue(stream, v)
+	}
+	stream.WriteArrayEnd()
+}
+
+func writeObject(stream *jsoniter.Stream, vals map[string]*Value) {
+	if vals == nil {
+		stream.WriteNil()
+		return
+	}
+
+	keysPtr := keysPool.Get().(*[]string)
+	keys := (*keysPtr)[:0]
+	for k := range vals {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	stream.WriteObjectStart()
+	for i, k := range keys {
+		if i > 0 {
+			stream.WriteMore()
+		}
+		writeObjectField(stream, k)
+		writeValue(stream, vals[k])
+	}
+	stream.WriteObjectEnd()
+
+	clear(keys)
+	*keysPtr = keys[:0]
+	keysPool.Put(keysPtr)
+}
+
+// writeObjectField writes key the way the config of the stream encodes map
+// keys, which may escape HTML characters.
+func writeObjectField(stream *jsoniter.Stream, key string) {
+	if !strings.ContainsAny(key, "<>&\u2028\u2029") {
+		stream.WriteObjectField(key)
+		return
+	}
+
+	format := streamKeyFormatOf(stream)
+	if format.escapeHTML {
+		stream.WriteStringWithHTMLEscaped(key)
+	} else {
+		stream.WriteString(key)
+	}
+	if format.indent {
+		stream.WriteRaw(": ")
+	} else {
+		stream.WriteRaw(":")
+	}
+}
+
+// streamKeyFormat is how a config writes map keys.
+type streamKeyFormat struct {
+	escapeHTML bool
+	indent     bool
+}
+
+var streamKeyFormats sync.Map // jsoniter.API -> streamKeyFormat
+
+// streamKeyFormatOf returns the key format of the config of stream, found
+// once per config by encoding a map with it.
+func streamKeyFormatOf(stream *jsoniter.Stream) streamKeyFormat {
+	api, ok := stream.Pool().(jsoniter.API)
+	if !ok {
+		return streamKeyFormat{}
+	}
+	if format, ok := streamKeyFormats.Load(api); ok {
+		return format.(streamKeyFormat)
+	}
+	format := streamKeyFormat{}
+	if probe, err := api.MarshalToString(map[string]int{"<": 0}); err == nil {
+		format.escapeHTML = strings.Contains(probe, `\u003c`)
+		format.indent = strings.Contains(probe, `": `)
+	}
+	streamKeyFormats.Store(api, format)
+	return format
+}
+
+// writeBytes writes bs in the form selected by SetBytesJSONMode.
+func writeBytes(stream *jsoniter.Stream, bs []byte) {
+	wrapped := GetBytesJSONMode() == BytesJSONWrapper
+	if wrapped {
+		stream.WriteObjectStart()
+		stream.WriteObjectField(BytesJSONKey)
+	}
+
+	bufPtr := bytesPool.Get().(*[]byte)
+	buf := append((*bufPtr)[:0], '"')
+	if !wrapped {
+		buf = append(buf, Base64Prefix...)
+	}
+	buf = base64.StdEncoding.AppendEncode(buf, bs)
+	buf = append(buf, '"')
+	_, _ = stream.Write(buf)
+	if wrapped {
+		stream.WriteObjectEnd()
+	}
+	*bufPtr = buf[:0]
+	bytesPool.Put(bufPtr)
+}
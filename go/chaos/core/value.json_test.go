@@ -90,3 +90,99 @@ func TestValuesCodec_Decode8(t *testing.T) {
 	assert.Equal(t, ValueKind_VALUE_KIND_INTEGER, vt.Value.GetKind())
 	assert.Equal(t, int64(0), vt.Value.GetInt64())
 }
+
+func TestValueCodec_EncodePackedArray(t *testing.T) {
+	tests := []struct {
+		name string
+		v    *Value
+		want string
+	}{
+		{name: "bool", v: NewPackedBoolArrayValue(true, false), want: `[true,false]`},
+		{name: "int64", v: NewPackedInt64ArrayValue(1, -2, 9223372036854775807), want: `[1,-2,9223372036854775807]`},
+		{name: "float64", v: NewPackedFloat64ArrayValue(0.5, 2), want: `[0.5,2]`},
+		{name: "string", v: NewPackedStringArrayValue("a", `"b"`), want: `["a","\"b\""]`},
+		{name: "empty", v: NewPackedInt64ArrayValue(), want: `[]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsoniter.ConfigFastest.MarshalToString(&ValueTag{Tag: tt.name, Value: tt.v})
+			assert.NoError(t, err)
+			assert.Equal(t, `{"tag":"`+tt.name+`","value":`+tt.want+`}`, got)
+
+			decoded := &ValueTag{}
+			assert.NoError(t, jsoniter.UnmarshalFromString(got, decoded))
+			again, err := jsoniter.ConfigFastest.MarshalToString(decoded)
+			assert.NoError(t, err)
+			assert.Equal(t, got, again)
+		})
+	}
+}
+
+func TestValueCodec_EncodeSortedKeys(t *testing.T) {
+	v := NewObjectValue(NewObject().SetInt("b", 1).SetString("a<", "x<").SetBytes("c", []byte("hi")).
+		SetValue("d", NewArrayValue(NewNullValue(), nil, NewObjectValue(NewObject()))))
+	for i := 0; i < 10; i++ {
+		s, err := jsoniter.ConfigFastest.MarshalToString(v)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"a<":"x<","b":1,"c":"b64.aGk=","d":[null,null,{}]}`, s)
+	}
+
+	s, err := jsoniter.ConfigCompatibleWithStandardLibrary.MarshalToString(v)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a\u003c":"x<","b":1,"c":"b64.aGk=","d":[null,null,{}]}`, s)
+
+	bs, err := jsoniter.ConfigCompatibleWithStandardLibrary.MarshalIndent(NewObjectValue(NewObject().SetInt("a<", 1)), "", "  ")
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n  \"a\\u003c\": 1\n}", string(bs))
+}
+
+func BenchmarkValueCodec_Encode(b *testing.B) {
+	items := make([]*Value, 0, 100)
+	for i := 0; i < 100; i++ {
+		items = append(items, NewObjectValue(NewObject().SetInt("id", i).SetString("name", "item").SetFloat64("price", 9.5).
+			SetBool("active", i%2 == 0).SetBytes("digest", []byte{1, 2, 3, 4}).SetStringArray("tags", "a", "b")))
+	}
+	v := NewObjectValue(NewObject().SetString("kind", "list").SetValue("items", NewArrayValue(items...)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := jsoniter.ConfigFastest.Marshal(v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValueCodec_EncodeStream(b *testing.B) {
+	items := make([]*Value, 0, 100)
+	for i := 0; i < 100; i++ {
+		items = append(items, NewObjectValue(NewObject().SetInt("id", i).SetString("name", "item").SetFloat64("price", 9.5).
+			SetBool("active", i%2 == 0).SetBytes("digest", []byte{1, 2, 3, 4}).SetStringArray("tags", "a", "b")))
+	}
+	v := NewObjectValue(NewObject().SetString("kind", "list").SetValue("items", NewArrayValue(items...)))
+	stream := jsoniter.ConfigFastest.BorrowStream(nil)
+	defer jsoniter.ConfigFastest.ReturnStream(stream)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stream.Reset(nil)
+		stream.WriteVal(v)
+	}
+}
+
+// BenchmarkValue_EncodeAsInterface is the generic path through intermediate
+// Go values, for comparison.
+func BenchmarkValue_EncodeAsInterface(b *testing.B) {
+	items := make([]*Value, 0, 100)
+	for i := 0; i < 100; i++ {
+		items = append(items, NewObjectValue(NewObject().SetInt("id", i).SetString("name", "item").SetFloat64("price", 9.5).
+			SetBool("active", i%2 == 0).SetBytes("digest", []byte{1, 2, 3, 4}).SetStringArray("tags", "a", "b")))
+	}
+	v := NewObjectValue(NewObject().SetString("kind", "list").SetValue("items", NewArrayValue(items...)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := jsoniter.ConfigFastest.Marshal(v.AsInterface()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
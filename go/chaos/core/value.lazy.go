@@ -0,0 +1,426 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// LazyValue is a JSON value backed by its raw bytes, the zero LazyValue being
+// null. Objects and arrays are only indexed down to the level that is
+// accessed, scalars and subtrees are only decoded into Values when asked for,
+// and encoding emits every subtree that was not modified byte-for-byte as it
+// was received. It suits proxies
+// which look at a few keys of large bodies before forwarding them.
+//
+// A LazyValue is not safe for concurrent use, even for reading, as accessing
+// it builds its index.
+type LazyValue struct {
+	raw []byte
+
+	// replaced holds a value set by Set or SetValue, raw is stale then
+	replaced *Value
+	decoded  *Value
+
+	indexed bool
+	fields  []*lazyField
+	elems   []*LazyValue
+	// changed marks keys added to or deleted from an object
+	changed bool
+}
+
+type lazyField struct {
+	key string
+	val *LazyValue
+}
+
+// NewLazyValue returns a LazyValue of raw, which is validated but neither
+// copied nor decoded.
+func NewLazyValue(raw []byte) (*LazyValue, error) {
+	raw = bytes.TrimSpace(raw)
+	if !json.Valid(raw) {
+		return nil, errors.New("LazyValue: invalid JSON")
+	}
+	return &LazyValue{raw: raw}, nil
+}
+
+// NewLazyValueFrom returns a modified LazyValue holding v.
+func NewLazyValueFrom(v *Value) *LazyValue {
+	if v == nil {
+		v = NewNullValue()
+	}
+	return &LazyValue{replaced: v}
+}
+
+// Raw returns the bytes the value was created with; they are stale once the
+// value is modified, use MarshalJSON then.
+func (l *LazyValue) Raw() []byte {
+	if l == nil {
+		return nil
+	}
+	return l.raw
+}
+
+// IsModified reports whether the value or any of its descendants was
+// modified since it was created.
+func (l *LazyValue) IsModified() bool {
+	if l == nil {
+		return false
+	}
+	if l.replaced != nil || l.changed {
+		return true
+	}
+	for _, f := range l.fields {
+		if f.val.IsModified() {
+			return true
+		}
+	}
+	for _, e := range l.elems {
+		if e.IsModified() {
+			return true
+		}
+	}
+	return false
+}
+
+// Kind returns the kind of the value, decoding only scalars.
+func (l *LazyValue) Kind() ValueKind {
+	switch {
+	case l == nil:
+		return ValueKind_VALUE_KIND_UNSPECIFIED
+	case l.replaced != nil:
+		return l.replaced.GetKind()
+	case len(l.raw) == 0:
+		return ValueKind_VALUE_KIND_NULL
+	}
+	switch l.raw[0] {
+	case '{':
+		return ValueKind_VALUE_KIND_OBJECT
+	case '[':
+		return ValueKind_VALUE_KIND_ARRAY
+	case 'n':
+		return ValueKind_VALUE_KIND_NULL
+	case 't', 'f':
+		return ValueKind_VALUE_KIND_BOOLEAN
+	}
+	v, _ := l.Value()
+	return v.GetKind()
+}
+
+// Value decodes the value, the result is cached until the value is modified
+// and must not be mutated.
+func (l *LazyValue) Value() (*Value, error) {
+	switch {
+	case l == nil:
+		return nil, nil
+	case l.replaced != nil:
+		return l.replaced, nil
+	case l.decoded != nil && !l.IsModified():
+		return l.decoded, nil
+	case len(l.raw) == 0:
+		return NewNullValue(), nil
+	}
+
+	if l.IsModified() {
+		switch l.raw[0] {
+		case '{':
+			obj := &Object{Vals: make(map[string]*Value, len(l.fields))}
+			for _, f := range l.fields {
+				v, err := f.val.Value()
+				if err != nil {
+					return nil, err
+				}
+				obj.Vals[f.key] = v
+			}
+			return NewObjectValue(obj), nil
+		case '[':
+			vals := make([]*Value, 0, len(l.elems))
+			for _, e := range l.elems {
+				v, err := e.Value()
+				if err != nil {
+					return nil, err
+				}
+				vals = append(vals, v)
+			}
+			return NewArrayValue(vals...), nil
+		}
+	}
+
+	v := &Value{}
+	if err := jsoniter.ConfigFastest.Unmarshal(l.raw, v); err != nil {
+		return nil, err
+	}
+	l.decoded = v
+	return v, nil
+}
+
+// Object decodes the value as an Object, or returns nil if it is not one.
+func (l *LazyValue) Object() (*Object, error) {
+	v, err := l.Value()
+	if err != nil {
+		return nil, err
+	}
+	return v.GetObject(), nil
+}
+
+// Keys returns the keys of an object in the order they were received.
+func (l *LazyValue) Keys() []string {
+	if !l.index() {
+		return nil
+	}
+	keys := make([]string, 0, len(l.fields))
+	for _, f := range l.fields {
+		keys = append(keys, f.key)
+	}
+	return keys
+}
+
+// Len returns the number of keys of an object or elements of an array.
+func (l *LazyValue) Len() int {
+	if !l.index() {
+		return 0
+	}
+	return len(l.fields) + len(l.elems)
+}
+
+// Get returns the value of key in an object, or nil.
+func (l *LazyValue) Get(key string) *LazyValue {
+	if !l.index() {
+		return nil
+	}
+	for _, f := range l.fields {
+		if f.key == key {
+			return f.val
+		}
+	}
+	return nil
+}
+
+// Index returns the i-th element of an array, or nil.
+func (l *LazyValue) Index(i int) *LazyValue {
+	if !l.index() || i < 0 || i >= len(l.elems) {
+		return nil
+	}
+	return l.elems[i]
+}
+
+// GetPath returns the value at the dotted path, see Value.GetPath.
+func (l *LazyValue) GetPath(path string) *LazyValue {
+	v := l
+	for _, segment := range splitPath(path) {
+		if v.Kind() == ValueKind_VALUE_KIND_ARRAY {
+			i, ok := pathIndex(segment, v.Len())
+			if !ok {
+				return nil
+			}
+			v = v.Index(i)
+		} else {
+			v = v.Get(segment)
+		}
+		if v == nil {
+			return nil
+		}
+	}
+	return v
+}
+
+// SetValue replaces the whole value.
+func (l *LazyValue) SetValue(v *Value) {
+	*l = *NewLazyValueFrom(v)
+}
+
+// Set sets key of an object to v. Keys added are encoded after the received
+// ones.
+func (l *LazyValue) Set(key string, v *Value) error {
+	if !l.index() || len(l.raw) == 0 || l.raw[0] != '{' {
+		return fmt.Errorf("LazyValue.Set %q: not an object", key)
+	}
+	if field := l.Get(key); field != nil {
+		field.SetValue(v)
+		return nil
+	}
+	l.fields = append(l.fields, &lazyField{key: key, val: NewLazyValueFrom(v)})
+	l.changed = true
+	return nil
+}
+
+// Delete removes key from an object.
+func (l *LazyValue) Delete(key string) {
+	if !l.index() {
+		return
+	}
+	for i, f := range l.fields {
+		if f.key == key {
+			l.fields = append(l.fields[:i], l.fields[i+1:]...)
+			l.changed = true
+			return
+		}
+	}
+}
+
+func (l *LazyValue) MarshalJSON() ([]byte, error) {
+	if l == nil {
+		return []byte("null"), nil
+	}
+	if !l.IsModified() {
+		if len(l.raw) == 0 {
+			return []byte("null"), nil
+		}
+		return append([]byte(nil), l.raw...), nil
+	}
+	stream := jsoniter.ConfigFastest.BorrowStream(nil)
+	defer jsoniter.ConfigFastest.ReturnStream(stream)
+	l.encode(stream)
+	if stream.Error != nil {
+		return nil, stream.Error
+	}
+	return append([]byte(nil), stream.Buffer()...), nil
+}
+
+// UnmarshalJSON keeps a copy of data, as decoders reuse their buffers.
+func (l *LazyValue) UnmarshalJSON(data []byte) error {
+	v, err := NewLazyValue(append([]byte(nil), data...))
+	if err != nil {
+		return err
+	}
+	*l = *v
+	return nil
+}
+
+func (l *LazyValue) encode(stream *jsoniter.Stream) {
+	switch {
+	case l.replaced != nil:
+		stream.WriteVal(l.replaced)
+	case len(l.raw) == 0:
+		stream.WriteNil()
+	case !l.IsModified():
+		_, _ = stream.Write(l.raw)
+	case l.raw[0] == '{':
+		stream.WriteObjectStart()
+		for i, f := range l.fields {
+			if i > 0 {
+				stream.WriteMore()
+			}
+			stream.WriteObjectField(f.key)
+			f.val.encode(stream)
+		}
+		stream.WriteObjectEnd()
+	default:
+		stream.WriteArrayStart()
+		for i, e := range l.elems {
+			if i > 0 {
+				stream.WriteMore()
+			}
+			e.encode(stream)
+		}
+		stream.WriteArrayEnd()
+	}
+}
+
+// index splits the raw bytes of an object or array into its children,
+// without copying them. It reports whether l is an object or array.
+func (l *LazyValue) index() bool {
+	if l == nil || l.replaced != nil || len(l.raw) == 0 {
+		return false
+	}
+	if l.indexed {
+		return true
+	}
+
+	raw := l.raw
+	switch raw[0] {
+	case '{':
+		for i := skipJSONSpace(raw, 1); raw[i] != '}'; {
+			end := skipJSONString(raw, i)
+			key := raw[i+1 : end-1]
+			i = skipJSONSpace(raw, end)
+			i = skipJSONSpace(raw, i+1) // ':'
+			valEnd := skipJSONValue(raw, i)
+			l.fields = append(l.fields, &lazyField{key: unquoteJSONKey(key), val: &LazyValue{raw: raw[i:valEnd]}})
+			i = skipJSONSpace(raw, valEnd)
+			if raw[i] == ',' {
+				i = skipJSONSpace(raw, i+1)
+			}
+		}
+	case '[':
+		for i := skipJSONSpace(raw, 1); raw[i] != ']'; {
+			end := skipJSONValue(raw, i)
+			l.elems = append(l.elems, &LazyValue{raw: raw[i:end]})
+			i = skipJSONSpace(raw, end)
+			if raw[i] == ',' {
+				i = skipJSONSpace(raw, i+1)
+			}
+		}
+	default:
+		return false
+	}
+	l.indexed = true
+	return true
+}
+
+func unquoteJSONKey(key []byte) string {
+	if bytes.IndexByte(key, '\\') < 0 {
+		return string(key)
+	}
+	var s string
+	_ = jsoniter.ConfigFastest.Unmarshal(append(append([]byte{'"'}, key...), '"'), &s)
+	return s
+}
+
+// The scanners below assume valid JSON, which NewLazyValue checks.
+
+func skipJSONSpace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\n' || data[i] == '\r' || data[i] == '\t') {
+		i++
+	}
+	return i
+}
+
+// skipJSONString returns the index after the closing quote of the string
+// starting at i.
+func skipJSONString(data []byte, i int) int {
+	for i++; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return i
+}
+
+// skipJSONValue returns the index after the value starting at i.
+func skipJSONValue(data []byte, i int) int {
+	switch data[i] {
+	case '"':
+		return skipJSONString(data, i)
+	case '{', '[':
+		depth := 0
+		for ; i < len(data); i++ {
+			switch data[i] {
+			case '"':
+				i = skipJSONString(data, i) - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return i + 1
+				}
+			}
+		}
+		return i
+	default:
+		for ; i < len(data); i++ {
+			switch data[i] {
+			case ',', '}', ']', ' ', '\n', '\r', '\t':
+				return i
+			}
+		}
+		return i
+	}
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazyValue_Get(t *testing.T) {
+	l, err := NewLazyValue([]byte(`{ "id" : "42",
+  "user": {"name": "ann", "tags": ["a", "b"], "score": 1.50},
+  "items": [ {"sku": "x\"1", "qty": 2}, {"sku": "y", "qty": 10000000000000000000} ],
+  "escaped": null, "flag": true }`))
+	assert.NoError(t, err)
+	assert.Equal(t, ValueKind_VALUE_KIND_OBJECT, l.Kind())
+	assert.Equal(t, []string{"id", "user", "items", "escaped", "flag"}, l.Keys())
+	assert.Equal(t, `"42"`, string(l.Get("id").Raw()))
+	assert.Equal(t, `{"name": "ann", "tags": ["a", "b"], "score": 1.50}`, string(l.Get("user").Raw()))
+
+	name, err := l.GetPath("user.name").Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "ann", name.GetString())
+	assert.Equal(t, ValueKind_VALUE_KIND_NUMBER, l.GetPath("user.score").Kind())
+	assert.Equal(t, 2, l.Get("items").Len())
+	assert.Equal(t, `"x\"1"`, string(l.GetPath("items.0.sku").Raw()))
+	assert.Equal(t, `10000000000000000000`, string(l.GetPath("items.1.qty").Raw()))
+	assert.Equal(t, ValueKind_VALUE_KIND_NULL, l.Get("escaped").Kind())
+	assert.Nil(t, l.GetPath("items.2"))
+	assert.Nil(t, l.GetPath("id.x"))
+
+	obj, err := l.Object()
+	assert.NoError(t, err)
+	assert.Equal(t, "42", obj.GetString("id"))
+	assert.Equal(t, uint64(10000000000000000000), obj.GetPath("items.1.qty").GetUint64())
+
+	_, err = NewLazyValue([]byte(`{"a":`))
+	assert.Error(t, err)
+}
+
+func TestLazyValue_MarshalJSON(t *testing.T) {
+	text := `{ "id" : "42",
+  "user": {"name": "ann", "tags": ["a", "b"], "score": 1.50},
+  "items": [ {"sku": "x\"1", "qty": 2}, {"sku": "y", "qty": 10000000000000000000} ],
+  "escaped": null, "flag": true }`
+	l, err := NewLazyValue([]byte(text))
+	assert.NoError(t, err)
+	l.GetPath("user.name")
+	bs, err := l.MarshalJSON()
+	assert.NoError(t, err)
+	assert.Equal(t, text, string(bs), "untouched values are emitted as received")
+	bs[0] = '['
+	assert.Equal(t, byte('{'), l.Raw()[0], "the returned bytes are a copy")
+
+	assert.NoError(t, l.Get("user").Set("name", NewStringValue("bob")))
+	assert.NoError(t, l.Set("trace", NewStringValue("t1")))
+	l.Delete("flag")
+	l.GetPath("items.0.qty").SetValue(NewIntValue(3))
+	assert.True(t, l.IsModified())
+
+	bs, err = l.MarshalJSON()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":"42","user":{"name":"bob","tags":["a", "b"],"score":1.50},`+
+		`"items":[{"sku":"x\"1","qty":3},{"sku": "y", "qty": 10000000000000000000}],`+
+		`"escaped":null,"trace":"t1"}`, string(bs))
+
+	obj, err := l.Object()
+	assert.NoError(t, err)
+	assert.Equal(t, "bob", obj.GetPath("user.name").GetString())
+	assert.Equal(t, int64(3), obj.GetPath("items.0.qty").GetInt64())
+	assert.Nil(t, obj.GetValue("flag"))
+
+	assert.Error(t, l.Get("id").Set("x", NewNullValue()))
+}
+
+func TestLazyValue_Zero(t *testing.T) {
+	l := &LazyValue{}
+	assert.Equal(t, ValueKind_VALUE_KIND_NULL, l.Kind())
+	v, err := l.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, ValueKind_VALUE_KIND_NULL, v.GetKind())
+	bs, err := l.MarshalJSON()
+	assert.NoError(t, err)
+	assert.Equal(t, "null", string(bs))
+	assert.Nil(t, l.Get("a"))
+	assert.Error(t, l.Set("a", NewNullValue()))
+
+	out, err := jsoniter.MarshalToString(map[string]*LazyValue{"a": l})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":null}`, out)
+}
+
+func TestLazyValue_Embedded(t *testing.T) {
+	type Envelope struct {
+		Kind string     `json:"kind"`
+		Body *LazyValue `json:"body"`
+	}
+	env := &Envelope{}
+	assert.NoError(t, jsoniter.UnmarshalFromString(`{"kind":"order","body":{"b": 1,  "a": [1, 2]}}`, env))
+	assert.Equal(t, []string{"b", "a"}, env.Body.Keys())
+
+	out, err := jsoniter.MarshalToString(env)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"kind":"order","body":{"b": 1,  "a": [1, 2]}}`, out)
+}
+
+func BenchmarkLazyValue_RouteAndForward(b *testing.B) {
+	item := `{"id":1,"name":"item 1","price":1.5,"tags":["a","b","c"],"meta":{"x":true,"y":null}}`
+	data := []byte(`{"route":"orders","items":[` + strings.Repeat(item+",", 999) + item + `]}`)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l, _ := NewLazyValue(data)
+		route, _ := l.Get("route").Value()
+		if route.GetString() != "orders" {
+			b.Fatal("unexpected route")
+		}
+		if _, err := l.MarshalJSON(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValue_EagerRouteAndForward(b *testing.B) {
+	item := `{"id":1,"name":"item 1","price":1.5,"tags":["a","b","c"],"meta":{"x":true,"y":null}}`
+	data := []byte(`{"route":"orders","items":[` + strings.Repeat(item+",", 999) + item + `]}`)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v := &Value{}
+		if err := jsoniter.ConfigFastest.Unmarshal(data, v); err != nil {
+			b.Fatal(err)
+		}
+		if v.GetPath("route").GetString() != "orders" {
+			b.Fatal("unexpected route")
+		}
+		if _, err := jsoniter.ConfigFastest.Marshal(v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestLazyValue_UnmarshalJSON(t *testing.T) {
+	decoder := json.NewDecoder(strings.NewReader(`{"a":"xxxxxx"} {"b":"yyyyyy"} [1, 2]`))
+	var values []*LazyValue
+	for decoder.More() {
+		l := &LazyValue{}
+		assert.NoError(t, decoder.Decode(l))
+		values = append(values, l)
+	}
+
+	var encoded []string
+	for _, l := range values {
+		bs, err := l.MarshalJSON()
+		assert.NoError(t, err)
+		encoded = append(encoded, string(bs))
+	}
+	assert.Equal(t, []string{`{"a":"xxxxxx"}`, `{"b":"yyyyyy"}`, `[1, 2]`}, encoded)
+}
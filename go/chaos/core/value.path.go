@@ -0,0 +1,97 @@
+package core
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PathSeparator separates the segments of a Value path. A segment is either
+// an Object key or, inside Values, a zero-based index, e.g. "items.0.name".
+const PathSeparator = "."
+
+func splitPath(path string) []string {
+	if len(path) == 0 {
+		return nil
+	}
+	return strings.Split(path, PathSeparator)
+}
+
+func joinPath(parent string, segment string) string {
+	if len(parent) == 0 {
+		return segment
+	}
+	return parent + PathSeparator + segment
+}
+
+func pathIndex(segment string, size int) (int, bool) {
+	i, err := strconv.Atoi(segment)
+	if err != nil || i < 0 || i >= size {
+		return 0, false
+	}
+	return i, true
+}
+
+// GetPath returns the Value at the dotted path below x, or nil if any segment
+// is missing. An empty path returns x itself.
+func (x *Value) GetPath(path string) *Value {
+	v := x
+	for _, segment := range splitPath(path) {
+		switch {
+		case v.GetObject() != nil:
+			v = v.GetObject().GetValue(segment)
+		case v.GetKind() == ValueKind_VALUE_KIND_ARRAY:
+			i, ok := pathIndex(segment, v.arrayLen())
+			if !ok {
+				return nil
+			}
+			v = v.arrayIndex(i)
+		default:
+			return nil
+		}
+		if v == nil {
+			return nil
+		}
+	}
+	return v
+}
+
+// GetPath returns the Value at the dotted path below x, or nil if any segment
+// is missing.
+func (x *Object) GetPath(path string) *Value {
+	if x == nil {
+		return nil
+	}
+	return NewObjectValue(x).GetPath(path)
+}
+
+func (x *Value) arrayLen() int {
+	switch v := x.GetVal().(type) {
+	case *Value_BoolValues:
+		return len(v.BoolValues.GetVals())
+	case *Value_Int64Values:
+		return len(v.Int64Values.GetVals())
+	case *Value_Float64Values:
+		return len(v.Float64Values.GetVals())
+	case *Value_StringValues:
+		return len(v.StringValues.GetVals())
+	default:
+		return len(x.GetValuesValue().GetVals())
+	}
+}
+
+// arrayIndex returns the i-th element of an array, boxing only that element
+// of packed arrays.
+func (x *Value) arrayIndex(i int) *Value {
+	switch v := x.GetVal().(type) {
+	case *Value_BoolValues:
+		return NewBoolValue(v.BoolValues.Vals[i])
+	case *Value_Int64Values:
+		return NewInt64Value(v.Int64Values.Vals[i])
+	case *Value_Float64Values:
+		return NewFloat64Value(v.Float64Values.Vals[i])
+	case *Value_StringValues:
+		return NewStringValue(v.StringValues.Vals[i])
+	default:
+		return x.GetValuesValue().GetVals()[i]
+	}
+}
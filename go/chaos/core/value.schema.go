@@ -0,0 +1,472 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chaos-io/core/go/chaos/core/strcase"
+)
+
+// StringFormat is a well-known format of string values.
+type StringFormat string
+
+const (
+	StringFormatTimestamp StringFormat = "date-time"
+	StringFormatURL       StringFormat = "uri"
+	StringFormatUUID      StringFormat = "uuid"
+	StringFormatEmail     StringFormat = "email"
+)
+
+var (
+	uuidPattern  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	emailPattern = regexp.MustCompile(`^[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}$`)
+)
+
+func detectStringFormat(s string) StringFormat {
+	switch {
+	case uuidPattern.MatchString(s):
+		return StringFormatUUID
+	case emailPattern.MatchString(s):
+		return StringFormatEmail
+	}
+	if _, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return StringFormatTimestamp
+	}
+	if u, err := url.Parse(s); err == nil && len(u.Scheme) > 0 && len(u.Host) > 0 {
+		return StringFormatURL
+	}
+	return ""
+}
+
+// DefaultSchemaEnumLimit is the default maximum number of distinct strings
+// of a path to be reported as enum candidates.
+const DefaultSchemaEnumLimit = 10
+
+// SchemaInferrer builds a merged schema from a sample of Values, e.g. the
+// events of a new source.
+type SchemaInferrer struct {
+	root      *SchemaNode
+	enumLimit int
+}
+
+func NewSchemaInferrer() *SchemaInferrer {
+	return &SchemaInferrer{root: newSchemaNode(), enumLimit: DefaultSchemaEnumLimit}
+}
+
+// WithEnumLimit sets the maximum number of distinct strings of enum
+// candidates, 0 disables enum detection.
+func (s *SchemaInferrer) WithEnumLimit(limit int) *SchemaInferrer {
+	s.enumLimit = limit
+	return s
+}
+
+// Add merges the shape of vals into the schema.
+func (s *SchemaInferrer) Add(vals ...*Value) *SchemaInferrer {
+	for _, v := range vals {
+		s.root.add(v, s.enumLimit)
+	}
+	return s
+}
+
+// AddObjects merges the shape of objects into the schema.
+func (s *SchemaInferrer) AddObjects(objects ...*Object) *SchemaInferrer {
+	for _, obj := range objects {
+		s.root.add(NewObjectValue(obj), s.enumLimit)
+	}
+	return s
+}
+
+// Schema returns the schema of the values added so far.
+func (s *SchemaInferrer) Schema() *SchemaNode {
+	return s.root
+}
+
+// InferSchema returns the merged schema of vals.
+func InferSchema(vals ...*Value) *SchemaNode {
+	return NewSchemaInferrer().Add(vals...).Schema()
+}
+
+// SchemaNode is the inferred schema of one path of the sample.
+type SchemaNode struct {
+	// Count is the number of values seen at the path, nulls included.
+	Count int
+	// Kinds counts the values seen per kind.
+	Kinds map[ValueKind]int
+	// Missing counts the objects holding the parent path but not this key.
+	Missing int
+
+	// Min and Max are the range of the integers and numbers.
+	Min float64
+	Max float64
+
+	// MinLength and MaxLength are the range of the string lengths in runes.
+	MinLength int
+	MaxLength int
+	// Formats counts the strings matching each format.
+	Formats map[StringFormat]int
+
+	// Properties are the keys of the objects at the path.
+	Properties map[string]*SchemaNode
+	// Items is the merged schema of the elements of the arrays at the path.
+	Items *SchemaNode
+
+	enum        map[string]int
+	enumOverrun bool
+	objects     int
+}
+
+func newSchemaNode() *SchemaNode {
+	return &SchemaNode{Kinds: map[ValueKind]int{}, Formats: map[StringFormat]int{}, enum: map[string]int{}}
+}
+
+func (n *SchemaNode) add(v *Value, enumLimit int) {
+	kind := v.GetKind()
+	if kind == ValueKind_VALUE_KIND_UNSPECIFIED {
+		kind = ValueKind_VALUE_KIND_NULL
+	}
+	n.Count++
+	n.Kinds[kind]++
+
+	switch kind {
+	case ValueKind_VALUE_KIND_INTEGER, ValueKind_VALUE_KIND_NUMBER:
+		f, _, _ := CoerceStrict.ToFloat64(v)
+		if n.Kinds[ValueKind_VALUE_KIND_INTEGER]+n.Kinds[ValueKind_VALUE_KIND_NUMBER] == 1 {
+			n.Min, n.Max = f, f
+		} else {
+			n.Min, n.Max = math.Min(n.Min, f), math.Max(n.Max, f)
+		}
+	case ValueKind_VALUE_KIND_STRING:
+		s := v.GetString()
+		length := len([]rune(s))
+		if n.Kinds[kind] == 1 {
+			n.MinLength, n.MaxLength = length, length
+		} else {
+			n.MinLength, n.MaxLength = min(n.MinLength, length), max(n.MaxLength, length)
+		}
+		if format := detectStringFormat(s); len(format) > 0 {
+			n.Formats[format]++
+		}
+		if !n.enumOverrun {
+			n.enum[s]++
+			if len(n.enum) > enumLimit {
+				n.enumOverrun, n.enum = true, nil
+			}
+		}
+	case ValueKind_VALUE_KIND_OBJECT:
+		if n.Properties == nil {
+			n.Properties = map[string]*SchemaNode{}
+		}
+		vals := v.GetObject().GetVals()
+		for key, prop := range n.Properties {
+			if _, ok := vals[key]; !ok {
+				prop.Missing++
+			}
+		}
+		for _, key := range sortedKeys(vals) {
+			prop, ok := n.Properties[key]
+			if !ok {
+				prop = newSchemaNode()
+				prop.Missing = n.objects
+				n.Properties[key] = prop
+			}
+			prop.add(vals[key], enumLimit)
+		}
+		n.objects++
+	case ValueKind_VALUE_KIND_ARRAY:
+		if n.Items == nil {
+			n.Items = newSchemaNode()
+		}
+		for _, item := range v.GetValues() {
+			n.Items.add(item, enumLimit)
+		}
+	}
+}
+
+// Nullable reports whether a null was seen at the path, or the key was
+// missing from some of the objects.
+func (n *SchemaNode) Nullable() bool {
+	return n.Kinds[ValueKind_VALUE_KIND_NULL] > 0 || n.Missing > 0
+}
+
+// Required reports whether the key was present in every object of the parent
+// path.
+func (n *SchemaNode) Required() bool {
+	return n.Missing == 0
+}
+
+// KindList returns the non-null kinds seen at the path, in the order of
+// ValueKind.
+func (n *SchemaNode) KindList() []ValueKind {
+	var kinds []ValueKind
+	for kind := range n.Kinds {
+		if kind != ValueKind_VALUE_KIND_NULL {
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i] < kinds[j] })
+	return kinds
+}
+
+// Format returns the format matched by every string seen at the path.
+func (n *SchemaNode) Format() StringFormat {
+	strs := n.Kinds[ValueKind_VALUE_KIND_STRING]
+	for format, count := range n.Formats {
+		if strs > 0 && count == strs {
+			return format
+		}
+	}
+	return ""
+}
+
+// Enum returns the enum candidates of the path in sorted order: the distinct
+// strings, when there were no more of them than the enum limit, and some
+// were seen more than once. Strings with a format are not enums.
+func (n *SchemaNode) Enum() []string {
+	strs := n.Kinds[ValueKind_VALUE_KIND_STRING]
+	if n.enumOverrun || len(n.enum) == 0 || len(n.enum) >= strs || len(n.Format()) > 0 {
+		return nil
+	}
+	vals := make([]string, 0, len(n.enum))
+	for s := range n.enum {
+		vals = append(vals, s)
+	}
+	sort.Strings(vals)
+	return vals
+}
+
+// JSONSchema exports the schema as a JSON Schema (draft 2020-12) document.
+func (n *SchemaNode) JSONSchema() *Object {
+	schema := n.jsonSchema()
+	schema.SetString("$schema", "https://json-schema.org/draft/2020-12/schema")
+	return schema
+}
+
+func (n *SchemaNode) jsonSchema() *Object {
+	schema := NewObject()
+	var types []string
+	for _, kind := range n.KindList() {
+		types = append(types, jsonSchemaType(kind))
+	}
+	if n.Kinds[ValueKind_VALUE_KIND_NULL] > 0 {
+		types = append(types, "null")
+	}
+	switch len(types) {
+	case 0:
+	case 1:
+		schema.SetString("type", types[0])
+	default:
+		schema.SetStringArray("type", types...)
+	}
+
+	if n.Kinds[ValueKind_VALUE_KIND_INTEGER]+n.Kinds[ValueKind_VALUE_KIND_NUMBER] > 0 {
+		schema.SetValue("minimum", jsonSchemaNumber(n.Min))
+		schema.SetValue("maximum", jsonSchemaNumber(n.Max))
+	}
+	if format := n.Format(); len(format) > 0 {
+		schema.SetString("format", string(format))
+	}
+	if enum := n.Enum(); len(enum) > 0 {
+		schema.SetStringArray("enum", enum...)
+	}
+	if n.Properties != nil {
+		props := NewObject()
+		var required []string
+		for _, key := range sortedNodeKeys(n.Properties) {
+			prop := n.Properties[key]
+			props.SetObject(key, prop.jsonSchema())
+			if prop.Required() {
+				required = append(required, key)
+			}
+		}
+		schema.SetObject("properties", props)
+		if len(required) > 0 {
+			schema.SetStringArray("required", required...)
+		}
+	}
+	if n.Items != nil && n.Items.Count > 0 {
+		schema.SetObject("items", n.Items.jsonSchema())
+	}
+	return schema
+}
+
+func jsonSchemaType(kind ValueKind) string {
+	switch kind {
+	case ValueKind_VALUE_KIND_BOOLEAN:
+		return "boolean"
+	case ValueKind_VALUE_KIND_INTEGER:
+		return "integer"
+	case ValueKind_VALUE_KIND_NUMBER:
+		return "number"
+	case ValueKind_VALUE_KIND_OBJECT:
+		return "object"
+	case ValueKind_VALUE_KIND_ARRAY:
+		return "array"
+	default:
+		return "string"
+	}
+}
+
+func jsonSchemaNumber(f float64) *Value {
+	if f == math.Trunc(f) && math.Abs(f) < 1<<63 {
+		return NewInt64Value(int64(f))
+	}
+	return NewFloat64Value(f)
+}
+
+// ProtoMessage suggests a proto3 message named name for the objects of the
+// schema, with nested messages for nested objects and fino2 options for
+// nullability, string formats, enums and integer ranges. Types which can not
+// be narrowed down are mapped to the chaos.core Value, Values and Object.
+func (n *SchemaNode) ProtoMessage(name string) string {
+	sb := &strings.Builder{}
+	n.writeProtoMessage(sb, strcase.ToCamel(name), "", true)
+	return sb.String()
+}
+
+func (n *SchemaNode) writeProtoMessage(sb *strings.Builder, name string, indent string, top bool) {
+	fmt.Fprintf(sb, "%smessage %s {\n", indent, name)
+	if top {
+		fmt.Fprintf(sb, "%s  option (fino2.model) = {generate: true};\n", indent)
+	}
+
+	body := top
+	keys := sortedNodeKeys(n.Properties)
+	for _, key := range keys {
+		if typ := n.Properties[key].nestedObject(); typ != nil {
+			if body {
+				sb.WriteString("\n")
+			}
+			typ.writeProtoMessage(sb, strcase.ToCamel(key), indent+"  ", false)
+			body = true
+		}
+	}
+	if len(keys) > 0 && body {
+		sb.WriteString("\n")
+	}
+	for i, key := range keys {
+		prop := n.Properties[key]
+		field := strcase.ToSnake(key)
+		fmt.Fprintf(sb, "%s  %s %s = %d", indent, prop.protoType(key), field, i+1)
+		var options []string
+		if field != key {
+			options = append(options, fmt.Sprintf("json_name = %q", key))
+		}
+		options = append(options, prop.protoOptions()...)
+		if len(options) > 0 {
+			fmt.Fprintf(sb, " [%s]", strings.Join(options, ", "))
+		}
+		sb.WriteString(";\n")
+	}
+	fmt.Fprintf(sb, "%s}\n", indent)
+}
+
+// nestedObject returns the schema of the objects at the path, if the path
+// holds nothing but objects or arrays of objects.
+func (n *SchemaNode) nestedObject() *SchemaNode {
+	kinds := n.KindList()
+	if len(kinds) != 1 {
+		return nil
+	}
+	switch kinds[0] {
+	case ValueKind_VALUE_KIND_OBJECT:
+		if len(n.Properties) > 0 {
+			return n
+		}
+	case ValueKind_VALUE_KIND_ARRAY:
+		if n.Items != nil && len(n.Items.KindList()) == 1 && n.Items.KindList()[0] == ValueKind_VALUE_KIND_OBJECT && len(n.Items.Properties) > 0 {
+			return n.Items
+		}
+	}
+	return nil
+}
+
+func (n *SchemaNode) protoType(key string) string {
+	kinds := n.KindList()
+	if len(kinds) == 2 && kinds[0] == ValueKind_VALUE_KIND_INTEGER && kinds[1] == ValueKind_VALUE_KIND_NUMBER {
+		return "double"
+	}
+	if len(kinds) != 1 {
+		return "chaos.core.Value"
+	}
+
+	switch kinds[0] {
+	case ValueKind_VALUE_KIND_BOOLEAN:
+		return "bool"
+	case ValueKind_VALUE_KIND_INTEGER:
+		if n.Min >= 0 && n.Max > math.MaxInt64 {
+			return "uint64"
+		}
+		return "int64"
+	case ValueKind_VALUE_KIND_NUMBER:
+		return "double"
+	case ValueKind_VALUE_KIND_STRING:
+		if n.Format() == StringFormatTimestamp {
+			return "chaos.core.Timestamp"
+		}
+		return "string"
+	case ValueKind_VALUE_KIND_BYTES:
+		return "bytes"
+	case ValueKind_VALUE_KIND_OBJECT:
+		if len(n.Properties) > 0 {
+			return strcase.ToCamel(key)
+		}
+		return "chaos.core.Object"
+	case ValueKind_VALUE_KIND_ARRAY:
+		if n.Items == nil || n.Items.Count == 0 {
+			return "chaos.core.Values"
+		}
+		items := n.Items.KindList()
+		if len(items) == 1 && items[0] == ValueKind_VALUE_KIND_ARRAY {
+			return "repeated chaos.core.Values"
+		}
+		return "repeated " + n.Items.protoType(key)
+	}
+	return "chaos.core.Value"
+}
+
+func (n *SchemaNode) protoOptions() []string {
+	var db, validate []string
+	if n.Nullable() {
+		db = append(db, "null: true")
+	} else {
+		validate = append(validate, "required: true")
+	}
+
+	switch n.Format() {
+	case StringFormatEmail:
+		validate = append(validate, "email: true")
+	case StringFormatURL:
+		validate = append(validate, "url: true")
+	case StringFormatUUID:
+		validate = append(validate, "uuid: true")
+	}
+	if enum := n.Enum(); len(enum) > 0 {
+		validate = append(validate, fmt.Sprintf("oneof: %q", strings.Join(enum, " ")))
+	}
+	if kinds := n.KindList(); len(kinds) == 1 && kinds[0] == ValueKind_VALUE_KIND_INTEGER && n.Max <= math.MaxInt64 {
+		validate = append(validate, fmt.Sprintf("min: %d", int64(n.Min)), fmt.Sprintf("max: %d", int64(n.Max)))
+	}
+
+	var options []string
+	if len(db) > 0 {
+		options = append(options, fmt.Sprintf("(fino2.db) = {%s}", strings.Join(db, ", ")))
+	}
+	if len(validate) > 0 {
+		options = append(options, fmt.Sprintf("(fino2.validate) = {%s}", strings.Join(validate, ", ")))
+	}
+	return options
+}
+
+func sortedNodeKeys(m map[string]*SchemaNode) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInferSchema(t *testing.T) {
+	var vals []*Value
+	assert.NoError(t, jsoniter.UnmarshalFromString(`[
+		{"id":"7d444840-9dc0-11d1-b245-5ffdce74fad2","type":"click","count":1,"score":0.5,"at":"2024-05-01T08:00:00Z","user":{"email":"a@b.io","homepage":"https://a.io"},"tags":["x"]},
+		{"id":"9d444840-9dc0-11d1-b245-5ffdce74fad2","type":"view","count":20,"score":3,"at":"2024-05-02T08:00:00Z","user":{"email":"c@d.io"},"tags":[],"extra":null},
+		{"id":"8d444840-9dc0-11d1-b245-5ffdce74fad2","type":"click","count":3,"score":1.5,"at":"2024-05-03T08:00:00Z","user":{"email":"e@f.io"},"tags":["y","z"],"extra":{"a":1}}
+	]`, &vals))
+
+	schema := InferSchema(vals...)
+	assert.Equal(t, 3, schema.Count)
+	assert.Equal(t, []ValueKind{ValueKind_VALUE_KIND_OBJECT}, schema.KindList())
+
+	id := schema.Properties["id"]
+	assert.Equal(t, StringFormatUUID, id.Format())
+	assert.Nil(t, id.Enum())
+	assert.False(t, id.Nullable())
+
+	assert.Equal(t, []string{"click", "view"}, schema.Properties["type"].Enum())
+	assert.Equal(t, StringFormatTimestamp, schema.Properties["at"].Format())
+
+	count := schema.Properties["count"]
+	assert.Equal(t, []ValueKind{ValueKind_VALUE_KIND_INTEGER}, count.KindList())
+	assert.Equal(t, 1.0, count.Min)
+	assert.Equal(t, 20.0, count.Max)
+
+	score := schema.Properties["score"]
+	assert.Equal(t, []ValueKind{ValueKind_VALUE_KIND_INTEGER, ValueKind_VALUE_KIND_NUMBER}, score.KindList())
+	assert.Equal(t, 0.5, score.Min)
+
+	user := schema.Properties["user"]
+	assert.Equal(t, StringFormatEmail, user.Properties["email"].Format())
+	assert.Equal(t, StringFormatURL, user.Properties["homepage"].Format())
+	assert.True(t, user.Properties["homepage"].Nullable())
+	assert.Equal(t, 2, user.Properties["homepage"].Missing)
+
+	extra := schema.Properties["extra"]
+	assert.True(t, extra.Nullable())
+	assert.Equal(t, 1, extra.Missing)
+	assert.Equal(t, 1, extra.Kinds[ValueKind_VALUE_KIND_NULL])
+
+	tags := schema.Properties["tags"]
+	assert.Equal(t, []ValueKind{ValueKind_VALUE_KIND_STRING}, tags.Items.KindList())
+	assert.Equal(t, 3, tags.Items.Count)
+}
+
+func TestSchemaNode_JSONSchema(t *testing.T) {
+	var vals []*Value
+	assert.NoError(t, jsoniter.UnmarshalFromString(`[
+		{"id":"7d444840-9dc0-11d1-b245-5ffdce74fad2","type":"click","count":1,"score":0.5,"at":"2024-05-01T08:00:00Z","user":{"email":"a@b.io","homepage":"https://a.io"},"tags":["x"]},
+		{"id":"9d444840-9dc0-11d1-b245-5ffdce74fad2","type":"view","count":20,"score":3,"at":"2024-05-02T08:00:00Z","user":{"email":"c@d.io"},"tags":[],"extra":null},
+		{"id":"8d444840-9dc0-11d1-b245-5ffdce74fad2","type":"click","count":3,"score":1.5,"at":"2024-05-03T08:00:00Z","user":{"email":"e@f.io"},"tags":["y","z"],"extra":{"a":1}}
+	]`, &vals))
+
+	schema := InferSchema(vals...).JSONSchema()
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", schema.GetString("$schema"))
+	assert.Equal(t, "object", schema.GetString("type"))
+	assert.Equal(t, []string{"at", "count", "id", "score", "tags", "type", "user"}, schema.GetValue("required").GetStringArray())
+
+	props := schema.GetObject("properties")
+	assert.Equal(t, "integer", props.GetPath("count.type").GetString())
+	assert.Equal(t, int64(20), props.GetPath("count.maximum").GetInt64())
+	assert.Equal(t, []string{"integer", "number"}, props.GetPath("score.type").GetStringArray())
+	assert.Equal(t, "date-time", props.GetPath("at.format").GetString())
+	assert.Equal(t, []string{"click", "view"}, props.GetPath("type.enum").GetStringArray())
+	assert.Equal(t, []string{"object", "null"}, props.GetPath("extra.type").GetStringArray())
+	assert.Equal(t, "string", props.GetPath("tags.items.type").GetString())
+	assert.Equal(t, []string{"email"}, props.GetPath("user.required").GetStringArray())
+}
+
+func TestSchemaNode_ProtoMessage(t *testing.T) {
+	var vals []*Value
+	assert.NoError(t, jsoniter.UnmarshalFromString(`[
+		{"id":"7d444840-9dc0-11d1-b245-5ffdce74fad2","type":"click","count":1,"score":0.5,"at":"2024-05-01T08:00:00Z","user":{"email":"a@b.io","homepage":"https://a.io"},"tags":["x"]},
+		{"id":"9d444840-9dc0-11d1-b245-5ffdce74fad2","type":"view","count":20,"score":3,"at":"2024-05-02T08:00:00Z","user":{"email":"c@d.io"},"tags":[],"extra":null},
+		{"id":"8d444840-9dc0-11d1-b245-5ffdce74fad2","type":"click","count":3,"score":1.5,"at":"2024-05-03T08:00:00Z","user":{"email":"e@f.io"},"tags":["y","z"],"extra":{"a":1}}
+	]`, &vals))
+
+	msg := InferSchema(vals...).ProtoMessage("page_event")
+	assert.Equal(t, `message PageEvent {
+  option (fino2.model) = {generate: true};
+
+  message Extra {
+    int64 a = 1 [(fino2.validate) = {required: true, min: 1, max: 1}];
+  }
+
+  message User {
+    string email = 1 [(fino2.validate) = {required: true, email: true}];
+    string homepage = 2 [(fino2.db) = {null: true}, (fino2.validate) = {url: true}];
+  }
+
+  chaos.core.Timestamp at = 1 [(fino2.validate) = {required: true}];
+  int64 count = 2 [(fino2.validate) = {required: true, min: 1, max: 20}];
+  Extra extra = 3 [(fino2.db) = {null: true}];
+  string id = 4 [(fino2.validate) = {required: true, uuid: true}];
+  double score = 5 [(fino2.validate) = {required: true}];
+  repeated string tags = 6 [(fino2.validate) = {required: true}];
+  string type = 7 [(fino2.validate) = {required: true, oneof: "click view"}];
+  User user = 8 [(fino2.validate) = {required: true}];
+}
+`, msg)
+}
+
+func TestSchemaInferrer_WithEnumLimit(t *testing.T) {
+	var vals []*Value
+	assert.NoError(t, jsoniter.UnmarshalFromString(`[{"type":"click"},{"type":"view"}]`, &vals))
+
+	schema := NewSchemaInferrer().WithEnumLimit(1).Add(vals...).Schema()
+	assert.Nil(t, schema.Properties["type"].Enum())
+}
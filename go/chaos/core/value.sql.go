@@ -0,0 +1,131 @@
+package core
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// SQLJSONMaxSize and SQLJSONMaxDepth bound the JSON columns accepted by the
+// Scan methods of Value, Object, Values and the boxed collections, so a bad
+// row fails fast instead of exhausting memory or the stack.
+var (
+	SQLJSONMaxSize  = 16 << 20
+	SQLJSONMaxDepth = 128
+)
+
+// Value Implement driver.Valuer and sql.Scanner interfaces on Value, stored as a JSON column
+func (x *Value) Value() (driver.Value, error) {
+	if x == nil {
+		return nil, nil
+	}
+	return sqlJSONValue(x)
+}
+
+func (x *Value) Scan(src interface{}) error {
+	x.Reset()
+	return scanSQLJSON(src, x)
+}
+
+func (x *Value) GormDataType() string {
+	return "json"
+}
+
+func (x *Object) Value() (driver.Value, error) {
+	if x == nil {
+		return nil, nil
+	}
+	return sqlJSONValue(x)
+}
+
+func (x *Object) Scan(src interface{}) error {
+	x.Reset()
+	return scanSQLJSON(src, x)
+}
+
+func (x *Object) GormDataType() string {
+	return "json"
+}
+
+func (x *Values) Value() (driver.Value, error) {
+	if x == nil {
+		return nil, nil
+	}
+	return sqlJSONValue(x)
+}
+
+func (x *Values) Scan(src interface{}) error {
+	x.Reset()
+	return scanSQLJSON(src, x)
+}
+
+func (x *Values) GormDataType() string {
+	return "json"
+}
+
+func sqlJSONValue(val any) (driver.Value, error) {
+	bytes, err := jsoniter.ConfigFastest.Marshal(val)
+	if err != nil {
+		return nil, err
+	}
+	return string(bytes), nil
+}
+
+func scanSQLJSON(src interface{}, dst any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("could not decode type %T -> %T", src, dst)
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+	if len(data) > SQLJSONMaxSize {
+		return fmt.Errorf("could not decode %T: json column of %d bytes exceeds the limit of %d bytes", dst, len(data), SQLJSONMaxSize)
+	}
+	if depth := jsonDepth(data); depth > SQLJSONMaxDepth {
+		return fmt.Errorf("could not decode %T: json column nested %d levels exceeds the limit of %d", dst, depth, SQLJSONMaxDepth)
+	}
+
+	// Unmarshal, unlike reading a single value, fails on data after the value
+	if err := jsoniter.ConfigFastest.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("could not decode %T: %w", dst, err)
+	}
+	return nil
+}
+
+// jsonDepth returns the maximal nesting of arrays and objects in data.
+func jsonDepth(data []byte) int {
+	depth, max := 0, 0
+	inString, escaped := false, false
+	for _, c := range data {
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			if c == '\\' {
+				escaped = true
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			depth++
+			if depth > max {
+				max = depth
+			}
+		case c == '}' || c == ']':
+			depth--
+		}
+	}
+	return max
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ driver.Valuer = (*Value)(nil)
+	_ sql.Scanner   = (*Object)(nil)
+	_ sql.Scanner   = (*StringsMap)(nil)
+)
+
+func TestObject_SQL(t *testing.T) {
+	obj := NewObject().SetString("name", "app").SetInt("port", 8080).SetStringArray("tags", "a", "b")
+	v, err := obj.Value()
+	assert.NoError(t, err)
+
+	scanned := &Object{}
+	assert.NoError(t, scanned.Scan([]byte(v.(string))))
+	assert.Equal(t, "app", scanned.GetString("name"))
+	assert.Equal(t, 8080, scanned.GetInt("port"))
+	assert.Equal(t, []string{"a", "b"}, scanned.GetStringArray("tags"))
+
+	assert.NoError(t, scanned.Scan(`{"other":true}`))
+	assert.Nil(t, scanned.GetValue("name"))
+	assert.True(t, scanned.GetBool("other"))
+
+	assert.NoError(t, scanned.Scan(nil))
+	assert.Error(t, scanned.Scan(10))
+
+	v, err = (*Object)(nil).Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+	assert.Equal(t, "json", scanned.GormDataType())
+}
+
+func TestValue_SQL(t *testing.T) {
+	val := NewArrayValue(NewIntValue(1), NewStringValue("x"))
+	v, err := val.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `[1,"x"]`, v)
+
+	scanned := &Value{}
+	assert.NoError(t, scanned.Scan(v))
+	assert.Equal(t, int64(1), scanned.GetValues()[0].GetInt64())
+
+	values := &Values{}
+	assert.NoError(t, values.Scan(`[true,2.5]`))
+	assert.Equal(t, 2, len(values.Vals))
+	assert.Equal(t, 2.5, values.Vals[1].GetFloat64())
+}
+
+func TestBoxed_SQL(t *testing.T) {
+	sv := NewStringValues("a", "b")
+	v, err := sv.Value()
+	assert.NoError(t, err)
+	scanned := &StringValues{}
+	assert.NoError(t, scanned.Scan(v))
+	assert.Equal(t, []string{"a", "b"}, scanned.Vals)
+
+	sm := &StringMap{}
+	assert.NoError(t, sm.Scan(`{"k":"v"}`))
+	assert.Equal(t, "v", sm.Vals["k"])
+
+	ssm := &StringsMap{}
+	assert.NoError(t, ssm.Scan([]byte(`{"k":["v1","v2"]}`)))
+	assert.Equal(t, []string{"v1", "v2"}, ssm.Vals["k"].Vals)
+}
+
+func TestScanSQLJSON_Limits(t *testing.T) {
+	obj := &Object{}
+	deep := strings.Repeat(`{"a":`, SQLJSONMaxDepth+1) + "1" + strings.Repeat("}", SQLJSONMaxDepth+1)
+	assert.Error(t, obj.Scan(deep))
+	assert.NoError(t, obj.Scan(`{"a":"{{{{[[["}`))
+	assert.NoError(t, obj.Scan([]byte(" {\"a\":1}\n\t ")))
+	assert.Error(t, obj.Scan(`{"a":1} garbage`))
+	assert.Error(t, obj.Scan(`{"a":1}{"b":2}`))
+
+	size := SQLJSONMaxSize
+	SQLJSONMaxSize = 8
+	defer func() { SQLJSONMaxSize = size }()
+	assert.Error(t, obj.Scan(`{"key":"value"}`))
+
+	assert.Error(t, obj.Scan(`{"key":`))
+}
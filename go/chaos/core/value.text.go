@@ -0,0 +1,523 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// The text syntax of Values is a compact JSON-like syntax keeping the kinds
+// apart, meant for debugging output and inline test fixtures:
+//
+//	{name: "Ann", age: 30, score: 1.0, raw: b"\x01\x02", tags: ["a"], note: null}
+//
+//   - integers have no fraction or exponent, numbers have one of them or are
+//     nan, inf or -inf.
+//   - strings are Go string literals, bytes are Go string literals prefixed
+//     by b.
+//   - keys are bare if they consist of letters, digits, '_', '-', '$', '@'
+//     and '#' without starting with a digit or '-', and quoted otherwise.
+//     Keys are printed sorted.
+//   - commas are optional, a trailing one is allowed, and // starts a comment
+//     up to the end of the line.
+//
+// Packed arrays are printed as arrays, and are parsed back as Values.
+
+const (
+	DefaultPrettyIndent    = "  "
+	DefaultPrettyMaxString = 64
+	DefaultPrettyMaxBytes  = 32
+)
+
+// ANSI colors of ValuePrinter.WithColor.
+const (
+	textColorKey     = "\x1b[36m"
+	textColorString  = "\x1b[32m"
+	textColorNumber  = "\x1b[33m"
+	textColorLiteral = "\x1b[35m"
+	textColorComment = "\x1b[90m"
+	textColorReset   = "\x1b[0m"
+)
+
+// ValuePrinter prints Values in the text syntax, by default compact on one
+// line, which ParseValueText parses back.
+type ValuePrinter struct {
+	indent    string
+	maxString int
+	maxBytes  int
+	kinds     bool
+	color     bool
+}
+
+func NewValuePrinter() *ValuePrinter {
+	return &ValuePrinter{}
+}
+
+// WithIndent prints one element per line indented by indent.
+func (p *ValuePrinter) WithIndent(indent string) *ValuePrinter {
+	p.indent = indent
+	return p
+}
+
+// WithTruncate cuts strings longer than maxString runes and bytes longer
+// than maxBytes, and marks them with "…". The output can not be parsed back
+// then. Zero does not truncate.
+func (p *ValuePrinter) WithTruncate(maxString, maxBytes int) *ValuePrinter {
+	p.maxString = maxString
+	p.maxBytes = maxBytes
+	return p
+}
+
+// WithKinds comments every value with its kind and containers with their
+// size. It only applies with WithIndent, as the comments end the line.
+func (p *ValuePrinter) WithKinds(kinds bool) *ValuePrinter {
+	p.kinds = kinds
+	return p
+}
+
+// WithColor highlights the output with ANSI colors for terminals.
+func (p *ValuePrinter) WithColor(color bool) *ValuePrinter {
+	p.color = color
+	return p
+}
+
+func (p *ValuePrinter) Print(v *Value) string {
+	sb := &strings.Builder{}
+	p.print(sb, v, 0)
+	if comment := p.comment(v); len(comment) > 0 && !isOpenContainer(v) {
+		p.writeComment(sb, comment)
+	}
+	return sb.String()
+}
+
+func (p *ValuePrinter) print(sb *strings.Builder, v *Value, depth int) {
+	switch v.GetKind() {
+	case ValueKind_VALUE_KIND_OBJECT:
+		vals := v.GetObject().GetVals()
+		if len(vals) == 0 {
+			sb.WriteString("{}")
+			return
+		}
+		sb.WriteByte('{')
+		p.writeComment(sb, p.comment(v))
+		for i, key := range sortedKeys(vals) {
+			p.writeSeparator(sb, i, depth+1)
+			p.writeColored(sb, textColorKey, textKey(key))
+			sb.WriteString(": ")
+			p.print(sb, vals[key], depth+1)
+			p.writeElementEnd(sb, vals[key])
+		}
+		p.writeClose(sb, '}', depth)
+	case ValueKind_VALUE_KIND_ARRAY:
+		vals := v.GetValues()
+		if len(vals) == 0 {
+			sb.WriteString("[]")
+			return
+		}
+		sb.WriteByte('[')
+		p.writeComment(sb, p.comment(v))
+		for i, val := range vals {
+			p.writeSeparator(sb, i, depth+1)
+			p.print(sb, val, depth+1)
+			p.writeElementEnd(sb, val)
+		}
+		p.writeClose(sb, ']', depth)
+	case ValueKind_VALUE_KIND_STRING:
+		s := v.GetString()
+		if p.maxString > 0 && utf8.RuneCountInString(s) > p.maxString {
+			cut := 0
+			for i := 0; i < p.maxString; i++ {
+				_, size := utf8.DecodeRuneInString(s[cut:])
+				cut += size
+			}
+			p.writeColored(sb, textColorString, strconv.Quote(s[:cut])+"…")
+			return
+		}
+		p.writeColored(sb, textColorString, strconv.Quote(s))
+	case ValueKind_VALUE_KIND_BYTES:
+		bs := v.GetBytes()
+		if p.maxBytes > 0 && len(bs) > p.maxBytes {
+			p.writeColored(sb, textColorString, "b"+strconv.Quote(string(bs[:p.maxBytes]))+"…")
+			return
+		}
+		p.writeColored(sb, textColorString, "b"+strconv.Quote(string(bs)))
+	case ValueKind_VALUE_KIND_INTEGER:
+		if n := v.GetNegativeValue(); n > 0 {
+			p.writeColored(sb, textColorNumber, "-"+strconv.FormatUint(n, 10))
+		} else {
+			p.writeColored(sb, textColorNumber, strconv.FormatUint(v.GetPositiveValue(), 10))
+		}
+	case ValueKind_VALUE_KIND_NUMBER:
+		p.writeColored(sb, textColorNumber, formatTextNumber(v.GetNumberValue()))
+	case ValueKind_VALUE_KIND_BOOLEAN:
+		p.writeColored(sb, textColorLiteral, strconv.FormatBool(v.GetBool()))
+	default:
+		p.writeColored(sb, textColorLiteral, "null")
+	}
+}
+
+func (p *ValuePrinter) writeSeparator(sb *strings.Builder, i int, depth int) {
+	if len(p.indent) > 0 {
+		sb.WriteByte('\n')
+		sb.WriteString(strings.Repeat(p.indent, depth))
+	} else if i > 0 {
+		sb.WriteString(", ")
+	}
+}
+
+// writeElementEnd ends an element of an indented container with a comma and
+// its comment, non-empty containers have theirs after the opening bracket.
+func (p *ValuePrinter) writeElementEnd(sb *strings.Builder, v *Value) {
+	if len(p.indent) == 0 {
+		return
+	}
+	sb.WriteByte(',')
+	if !isOpenContainer(v) {
+		p.writeComment(sb, p.comment(v))
+	}
+}
+
+func (p *ValuePrinter) writeClose(sb *strings.Builder, c byte, depth int) {
+	if len(p.indent) > 0 {
+		sb.WriteByte('\n')
+		sb.WriteString(strings.Repeat(p.indent, depth))
+	}
+	sb.WriteByte(c)
+}
+
+func (p *ValuePrinter) writeComment(sb *strings.Builder, comment string) {
+	if len(comment) > 0 {
+		sb.WriteByte(' ')
+		p.writeColored(sb, textColorComment, "// "+comment)
+	}
+}
+
+func (p *ValuePrinter) writeColored(sb *strings.Builder, color string, s string) {
+	if p.color {
+		sb.WriteString(color)
+		sb.WriteString(s)
+		sb.WriteString(textColorReset)
+		return
+	}
+	sb.WriteString(s)
+}
+
+// comment returns the kind comment of v, or "" if there is none to write.
+func (p *ValuePrinter) comment(v *Value) string {
+	if !p.kinds || len(p.indent) == 0 {
+		return ""
+	}
+	kind := strings.ToLower(strings.TrimPrefix(v.GetKind().String(), "VALUE_KIND_"))
+	switch v.GetKind() {
+	case ValueKind_VALUE_KIND_OBJECT:
+		return fmt.Sprintf("%s, %d keys", kind, len(v.GetObject().GetVals()))
+	case ValueKind_VALUE_KIND_ARRAY:
+		return fmt.Sprintf("%s, %d elements", kind, len(v.GetValues()))
+	case ValueKind_VALUE_KIND_STRING:
+		if n := utf8.RuneCountInString(v.GetString()); p.maxString > 0 && n > p.maxString {
+			return fmt.Sprintf("%s, %d chars", kind, n)
+		}
+	case ValueKind_VALUE_KIND_BYTES:
+		return fmt.Sprintf("%s, %d bytes", kind, len(v.GetBytes()))
+	}
+	return kind
+}
+
+// isOpenContainer reports whether v is printed over several lines.
+func isOpenContainer(v *Value) bool {
+	switch v.GetKind() {
+	case ValueKind_VALUE_KIND_OBJECT:
+		return len(v.GetObject().GetVals()) > 0
+	case ValueKind_VALUE_KIND_ARRAY:
+		return len(v.GetValues()) > 0
+	}
+	return false
+}
+
+func formatTextNumber(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "nan"
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	}
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s
+}
+
+func textKey(key string) string {
+	for i, r := range key {
+		if !isTextKeyRune(r, i == 0) {
+			return strconv.Quote(key)
+		}
+	}
+	if len(key) == 0 {
+		return `""`
+	}
+	return key
+}
+
+func isTextKeyRune(r rune, first bool) bool {
+	switch {
+	case r == '_' || r == '$' || r == '@' || r == '#':
+		return true
+	case r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+		return true
+	case r >= '0' && r <= '9' || r == '-':
+		return !first
+	}
+	return false
+}
+
+// Text returns v in the compact text syntax.
+func (x *Value) Text() string {
+	return NewValuePrinter().Print(x)
+}
+
+// Pretty returns v indented, with kind comments and long strings and bytes
+// truncated, for debugging.
+func (x *Value) Pretty() string {
+	return NewValuePrinter().
+		WithIndent(DefaultPrettyIndent).
+		WithTruncate(DefaultPrettyMaxString, DefaultPrettyMaxBytes).
+		WithKinds(true).
+		Print(x)
+}
+
+// Format implements fmt.Formatter: %v and %s print Text, %+v prints Pretty
+// and %q prints Text quoted.
+func (x *Value) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('+'):
+		_, _ = fmt.Fprint(f, x.Pretty())
+	case verb == 'v' || verb == 's':
+		_, _ = fmt.Fprint(f, x.Text())
+	case verb == 'q':
+		_, _ = fmt.Fprint(f, strconv.Quote(x.Text()))
+	default:
+		_, _ = fmt.Fprintf(f, "%%!%c(*core.Value=%s)", verb, x.Text())
+	}
+}
+
+// Format implements fmt.Formatter like Value.Format does.
+func (x *Object) Format(f fmt.State, verb rune) {
+	NewObjectValue(x).Format(f, verb)
+}
+
+// ParseValueText parses a Value in the text syntax.
+func ParseValueText(s string) (*Value, error) {
+	p := &valueTextParser{s: s}
+	p.skip()
+	v, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	p.skip()
+	if p.i < len(p.s) {
+		return nil, p.errorf("unexpected %q", p.s[p.i])
+	}
+	return v, nil
+}
+
+// MustParseValueText is like ParseValueText but panics on errors, for test
+// fixtures.
+func MustParseValueText(s string) *Value {
+	v, err := ParseValueText(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+type valueTextParser struct {
+	s string
+	i int
+}
+
+func (p *valueTextParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("value text at offset %d: %s", p.i, fmt.Sprintf(format, args...))
+}
+
+// skip skips spaces, commas and comments.
+func (p *valueTextParser) skip() {
+	for p.i < len(p.s) {
+		switch c := p.s[p.i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			p.i++
+		case strings.HasPrefix(p.s[p.i:], "//"):
+			end := strings.IndexByte(p.s[p.i:], '\n')
+			if end < 0 {
+				p.i = len(p.s)
+			} else {
+				p.i += end
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *valueTextParser) value() (*Value, error) {
+	if p.i >= len(p.s) {
+		return nil, p.errorf("unexpected end")
+	}
+
+	switch c := p.s[p.i]; {
+	case c == '{':
+		return p.object()
+	case c == '[':
+		return p.array()
+	case c == '"':
+		s, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		return NewStringValue(s), nil
+	case c == 'b' && strings.HasPrefix(p.s[p.i:], `b"`):
+		p.i++
+		s, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		return NewBytesValue([]byte(s)), nil
+	default:
+		return p.literal()
+	}
+}
+
+func (p *valueTextParser) object() (*Value, error) {
+	obj := NewObject()
+	p.i++
+	for {
+		p.skip()
+		if p.i >= len(p.s) {
+			return nil, p.errorf("unterminated object")
+		}
+		if p.s[p.i] == '}' {
+			p.i++
+			return NewObjectValue(obj), nil
+		}
+
+		key, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := obj.Vals[key]; ok {
+			return nil, p.errorf("duplicate key %q", key)
+		}
+		p.skip()
+		if p.i >= len(p.s) || p.s[p.i] != ':' {
+			return nil, p.errorf("expected ':' after key %q", key)
+		}
+		p.i++
+		p.skip()
+		val, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		obj.Vals[key] = val
+	}
+}
+
+func (p *valueTextParser) key() (string, error) {
+	if p.s[p.i] == '"' {
+		return p.quoted()
+	}
+	start := p.i
+	for p.i < len(p.s) {
+		r, size := utf8.DecodeRuneInString(p.s[p.i:])
+		if !isTextKeyRune(r, p.i == start) {
+			break
+		}
+		p.i += size
+	}
+	if p.i == start {
+		return "", p.errorf("expected a key")
+	}
+	return p.s[start:p.i], nil
+}
+
+func (p *valueTextParser) array() (*Value, error) {
+	vals := &Values{}
+	p.i++
+	for {
+		p.skip()
+		if p.i >= len(p.s) {
+			return nil, p.errorf("unterminated array")
+		}
+		if p.s[p.i] == ']' {
+			p.i++
+			return NewValuesValue(vals), nil
+		}
+		val, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		vals.Vals = append(vals.Vals, val)
+	}
+}
+
+// quoted reads a Go string literal.
+func (p *valueTextParser) quoted() (string, error) {
+	start := p.i
+	for p.i++; p.i < len(p.s); p.i++ {
+		switch p.s[p.i] {
+		case '\\':
+			p.i++
+		case '"':
+			p.i++
+			s, err := strconv.Unquote(p.s[start:p.i])
+			if err != nil {
+				p.i = start
+				return "", p.errorf("invalid string: %v", err)
+			}
+			return s, nil
+		}
+	}
+	p.i = start
+	return "", p.errorf("unterminated string")
+}
+
+// literal reads null, true, false, nan, inf and numbers.
+func (p *valueTextParser) literal() (*Value, error) {
+	start := p.i
+	for p.i < len(p.s) && strings.IndexByte("+-.0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ", p.s[p.i]) >= 0 {
+		p.i++
+	}
+	word := p.s[start:p.i]
+	switch word {
+	case "":
+		return nil, p.errorf("unexpected %q", p.s[p.i])
+	case "null":
+		return NewNullValue(), nil
+	case "true", "false":
+		return NewBoolValue(word == "true"), nil
+	case "nan":
+		return NewFloat64Value(math.NaN()), nil
+	case "inf", "+inf":
+		return NewFloat64Value(math.Inf(1)), nil
+	case "-inf":
+		return NewFloat64Value(math.Inf(-1)), nil
+	}
+
+	if !strings.ContainsAny(word, ".eE") {
+		if strings.HasPrefix(word, "-") {
+			if i, err := strconv.ParseInt(word, 10, 64); err == nil {
+				return NewInt64Value(i), nil
+			}
+		} else if u, err := strconv.ParseUint(strings.TrimPrefix(word, "+"), 10, 64); err == nil {
+			return NewUint64Value(u), nil
+		}
+	} else if f, err := strconv.ParseFloat(word, 64); err == nil {
+		return NewFloat64Value(f), nil
+	}
+	p.i = start
+	return nil, p.errorf("invalid literal %q", word)
+}
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestValue_Text(t *testing.T) {
+	val := NewObjectValue(NewObject().
+		SetString("name", "Ann").
+		SetInt("age", 30).
+		SetFloat64("score", 1).
+		SetValue("raw", NewBytesValue([]byte{1, 2})).
+		SetValue("tags", NewArrayValue(NewStringValue("a"), NewInt64Value(-2))).
+		SetValue("note", NewNullValue()).
+		SetObject("first name", NewObject()).
+		SetBool("ok", true))
+
+	text := `{age: 30, "first name": {}, name: "Ann", note: null, ok: true, raw: b"\x01\x02", score: 1.0, tags: ["a", -2]}`
+	assert.Equal(t, text, val.Text())
+	assert.Equal(t, text, fmt.Sprintf("%v", val))
+	assert.Equal(t, text, fmt.Sprint(val.GetObject()))
+
+	parsed, err := ParseValueText(text)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(val, parsed))
+
+	for _, f := range []float64{0.5, 1e21, -3, math.Inf(1), math.Inf(-1)} {
+		assert.True(t, proto.Equal(NewFloat64Value(f), MustParseValueText(NewFloat64Value(f).Text())))
+	}
+	assert.True(t, math.IsNaN(MustParseValueText("nan").GetFloat64()))
+	assert.Equal(t, uint64(math.MaxUint64), MustParseValueText("18446744073709551615").GetUint64())
+	assert.Equal(t, "[1, 2, 3]", NewPackedInt64ArrayValue(1, 2, 3).Text())
+}
+
+func TestValue_Pretty(t *testing.T) {
+	val := NewObjectValue(NewObject().
+		SetString("name", "Ann").
+		SetInt("age", 30).
+		SetFloat64("score", 1).
+		SetValue("raw", NewBytesValue([]byte{1, 2})).
+		SetValue("tags", NewArrayValue(NewStringValue("a"), NewInt64Value(-2))).
+		SetValue("note", NewNullValue()).
+		SetObject("first name", NewObject()).
+		SetBool("ok", true))
+
+	val.GetObject().SetString("long", strings.Repeat("é", 70))
+
+	assert.Equal(t, `{ // object, 9 keys
+  age: 30, // integer
+  "first name": {}, // object, 0 keys
+  long: "`+strings.Repeat("é", 64)+`"…, // string, 70 chars
+  name: "Ann", // string
+  note: null, // null
+  ok: true, // boolean
+  raw: b"\x01\x02", // bytes, 2 bytes
+  score: 1.0, // number
+  tags: [ // array, 2 elements
+    "a", // string
+    -2, // integer
+  ],
+}`, val.Pretty())
+	assert.Equal(t, val.Pretty(), fmt.Sprintf("%+v", val))
+	assert.Equal(t, "30 // integer", NewIntValue(30).Pretty())
+
+	colored := NewValuePrinter().WithColor(true).Print(NewObjectValue(NewObject().SetString("a", "x")))
+	assert.Equal(t, "{\x1b[36ma\x1b[0m: \x1b[32m\"x\"\x1b[0m}", colored)
+}
+
+func TestParseValueText(t *testing.T) {
+	// indented output without truncation parses back
+	val := NewObjectValue(NewObject().
+		SetString("name", "Ann").
+		SetInt("age", 30).
+		SetFloat64("score", 1).
+		SetValue("raw", NewBytesValue([]byte{1, 2})).
+		SetValue("tags", NewArrayValue(NewStringValue("a"), NewInt64Value(-2))).
+		SetValue("note", NewNullValue()).
+		SetObject("first name", NewObject()).
+		SetBool("ok", true))
+
+	parsed, err := ParseValueText(val.Pretty())
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(val, parsed))
+
+	val = MustParseValueText(`
+		// fixture
+		{
+			id: 7 name: "x"
+			$ref: "#/a", @attr: b"hi",
+			nested: {list: [1.5e3 true null []],},
+		}`)
+	assert.Equal(t, int64(7), val.GetPath("id").GetInt64())
+	assert.Equal(t, "#/a", val.GetPath("$ref").GetString())
+	assert.Equal(t, []byte("hi"), val.GetPath("@attr").GetBytes())
+	assert.Equal(t, 1500.0, val.GetPath("nested.list.0").GetFloat64())
+	assert.Equal(t, ValueKind_VALUE_KIND_ARRAY, val.GetPath("nested.list.3").GetKind())
+
+	for _, s := range []string{``, `{`, `[1`, `{a 1}`, `{a: 1, a: 2}`, `"x`, `"\q"`, `1x`, `tru`, `1 2`, `{1: 2}`, `"a"…`} {
+		_, err := ParseValueText(s)
+		assert.Error(t, err, s)
+	}
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"errors"
+	"strconv"
+)
+
+// WalkAction tells Walk how to continue after visiting a Value.
+type WalkAction int
+
+const (
+	// WalkContinue descends into the children of the visited value.
+	WalkContinue WalkAction = iota
+	// WalkSkip skips the children of the visited value.
+	WalkSkip
+	// WalkStop stops the walk.
+	WalkStop
+)
+
+// WalkFunc is called for every Value of a tree with its dotted path, see
+// Value.GetPath. The root has the empty path, nil entries of objects and
+// arrays are visited as nil.
+type WalkFunc func(path string, val *Value) WalkAction
+
+// TransformFunc returns the replacement of a Value. It is called bottom-up,
+// so val already holds the transformed children. Nil entries are passed as
+// null values, and returning nil drops the entry from its parent object or
+// array.
+type TransformFunc func(path string, val *Value) (*Value, error)
+
+// ErrWalkStop can be returned by a TransformFunc to stop the transform, the
+// values transformed so far are kept.
+var ErrWalkStop = errors.New("walk stopped")
+
+// Walk visits v and all its descendants depth-first, in sorted key order for
+// objects and index order for arrays. It returns false if the walk was
+// stopped by WalkStop.
+func Walk(v *Value, fn WalkFunc) bool {
+	return walk("", v, fn)
+}
+
+func walk(path string, v *Value, fn WalkFunc) bool {
+	switch fn(path, v) {
+	case WalkStop:
+		return false
+	case WalkSkip:
+		return true
+	}
+
+	switch {
+	case v.GetObject() != nil:
+		vals := v.GetObject().Vals
+		for _, k := range sortedKeys(vals) {
+			if !walk(joinPath(path, k), vals[k], fn) {
+				return false
+			}
+		}
+	case v.GetKind() == ValueKind_VALUE_KIND_ARRAY:
+		for i, val := range v.GetValues() {
+			if !walk(joinPath(path, strconv.Itoa(i)), val, fn) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Transform rebuilds v bottom-up through fn, leaving v untouched. Objects and
+// arrays are always copied, packed arrays into plain ones, other values are
+// passed to fn as they are.
+func Transform(v *Value, fn TransformFunc) (*Value, error) {
+	val, err := transform("", v, fn)
+	if errors.Is(err, ErrWalkStop) {
+		return val, nil
+	}
+	return val, err
+}
+
+func transform(path string, v *Value, fn TransformFunc) (*Value, error) {
+	switch {
+	case v == nil:
+		v = NewNullValue()
+	case v.GetObject() != nil:
+		vals := v.GetObject().Vals
+		obj := &Object{Vals: make(map[string]*Value, len(vals))}
+		keys := sortedKeys(vals)
+		for i, k := range keys {
+			val, err := transform(joinPath(path, k), vals[k], fn)
+			if val != nil {
+				obj.Vals[k] = val
+			}
+			if err != nil {
+				for _, rest := range keys[i+1:] {
+					obj.Vals[rest] = vals[rest]
+				}
+				return NewObjectValue(obj), err
+			}
+		}
+		v = NewObjectValue(obj)
+	case v.GetKind() == ValueKind_VALUE_KIND_ARRAY:
+		vals := v.GetValues()
+		values := make([]*Value, 0, len(vals))
+		for i, val := range vals {
+			transformed, err := transform(joinPath(path, strconv.Itoa(i)), val, fn)
+			if transformed != nil {
+				values = append(values, transformed)
+			}
+			if err != nil {
+				values = append(values, vals[i+1:]...)
+				return NewArrayValue(values...), err
+			}
+		}
+		v = NewArrayValue(values...)
+	}
+	return fn(path, v)
+}
+
+// TransformKeys returns a copy of v with every object key, at any depth,
+// mapped through fn.
+func TransformKeys(v *Value, fn func(key string) string) *Value {
+	val, _ := Transform(v, func(path string, val *Value) (*Value, error) {
+		if obj := val.GetObject(); obj != nil {
+			renamed := make(map[string]*Value, len(obj.Vals))
+			for _, k := range sortedKeys(obj.Vals) {
+				renamed[fn(k)] = obj.Vals[k]
+			}
+			obj.Vals = renamed
+		}
+		return val, nil
+	})
+	return val
+}
@@ -0,0 +1,94 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalk(t *testing.T) {
+	root := NewObjectValue(NewObject().
+		SetString("name", "app").
+		SetValue("empty", nil).
+		SetObject("owner", NewObject().SetString("email", "a@b.c")).
+		SetValue("items", NewArrayValue(NewIntValue(1), nil, NewObjectValue(NewObject().SetString("password", "secret")))))
+
+	var paths []string
+	completed := Walk(root, func(path string, val *Value) WalkAction {
+		paths = append(paths, path)
+		return WalkContinue
+	})
+	assert.True(t, completed)
+	assert.Equal(t, []string{"", "empty", "items", "items.0", "items.1", "items.2", "items.2.password", "name", "owner", "owner.email"}, paths)
+
+	paths = nil
+	Walk(root, func(path string, val *Value) WalkAction {
+		paths = append(paths, path)
+		if path == "items" {
+			return WalkSkip
+		}
+		return WalkContinue
+	})
+	assert.Equal(t, []string{"", "empty", "items", "name", "owner", "owner.email"}, paths)
+
+	paths = nil
+	completed = Walk(root, func(path string, val *Value) WalkAction {
+		paths = append(paths, path)
+		if val.GetKind() == ValueKind_VALUE_KIND_STRING {
+			return WalkStop
+		}
+		return WalkContinue
+	})
+	assert.False(t, completed)
+	assert.Equal(t, "items.2.password", paths[len(paths)-1])
+}
+
+func TestTransform(t *testing.T) {
+	origin := NewObjectValue(NewObject().
+		SetString("name", "app").
+		SetValue("empty", nil).
+		SetObject("owner", NewObject().SetString("email", "a@b.c")).
+		SetValue("items", NewArrayValue(NewIntValue(1), nil, NewObjectValue(NewObject().SetString("password", "secret")))))
+
+	redacted, err := Transform(origin, func(path string, val *Value) (*Value, error) {
+		if strings.HasSuffix(path, "password") {
+			return NewStringValue("***"), nil
+		}
+		if val.GetKind() == ValueKind_VALUE_KIND_NULL {
+			return nil, nil
+		}
+		return val, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "***", redacted.GetPath("items.1.password").GetString())
+	assert.Equal(t, 2, len(redacted.GetPath("items").GetValues()))
+	assert.Nil(t, redacted.GetPath("empty"))
+	assert.Equal(t, "secret", origin.GetPath("items.2.password").GetString())
+
+	stopped, err := Transform(origin, func(path string, val *Value) (*Value, error) {
+		if path == "items.0" {
+			return NewIntValue(2), ErrWalkStop
+		}
+		return val, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), stopped.GetPath("items.0").GetInt64())
+	assert.Equal(t, "a@b.c", stopped.GetPath("owner.email").GetString())
+}
+
+func TestObject_ToSnakeKeys(t *testing.T) {
+	obj := NewObject().
+		SetString("userName", "alice").
+		SetObject("homeAddress", NewObject().SetString("zipCode", "100000")).
+		SetObjectArray("phoneNumbers", NewObject().SetString("areaCode", "010"))
+
+	snake := obj.ToSnakeKeys()
+	assert.Equal(t, "alice", snake.GetString("user_name"))
+	assert.Equal(t, "100000", snake.GetPath("home_address.zip_code").GetString())
+	assert.Equal(t, "010", snake.GetPath("phone_numbers.0.area_code").GetString())
+	assert.Equal(t, "100000", obj.GetPath("homeAddress.zipCode").GetString())
+
+	camel := snake.ToLowerCamelKeys()
+	assert.Equal(t, obj.AsMap(), camel.AsMap())
+}
@@ -3,6 +3,9 @@ package core
 import (
 	"reflect"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestNewValue(t *testing.T) {
@@ -41,3 +44,57 @@ func TestNewValue(t *testing.T) {
 		})
 	}
 }
+
+func TestPackedArrayValue(t *testing.T) {
+	ints := []int64{1, -2, 3}
+	v := NewPackedInt64ArrayValue(ints...)
+	assert.True(t, v.IsPackedArray())
+	assert.Equal(t, ValueKind_VALUE_KIND_ARRAY, v.GetKind())
+	assert.Same(t, &ints[0], &v.GetInt64Array()[0], "read without copy")
+	assert.Equal(t, []float64{1, -2, 3}, v.GetFloat64Array())
+	assert.Equal(t, []any{uint64(1), int64(-2), uint64(3)}, v.AsInterface())
+	assert.Equal(t, int64(-2), v.GetPath("1").GetInt64())
+	assert.Nil(t, v.GetPath("3"))
+
+	floats := []float64{0.5, 1.5}
+	v = NewPackedFloat64ArrayValue(floats...)
+	assert.Same(t, &floats[0], &v.GetFloat64Array()[0])
+	assert.Equal(t, 2, len(v.GetValues()))
+
+	assert.Equal(t, []string{"a", "b"}, NewPackedStringArrayValue("a", "b").GetStringArray())
+	assert.Equal(t, []bool{true, false}, NewPackedBoolArrayValue(true, false).GetBoolArray())
+	assert.True(t, NewInt64ArrayValue(1, 2).IsPackedArray())
+	assert.True(t, NewStringArrayValue("a").IsPackedArray())
+	assert.False(t, NewUint64ArrayValue(1, 2).IsPackedArray())
+	assert.Equal(t, NewArrayValue(NewIntValue(1), NewIntValue(2)).AsInterface(), NewPackedInt64ArrayValue(1, 2).AsInterface())
+
+	copied := NewInt64ArrayValue(ints...)
+	ints[0] = 7
+	assert.Equal(t, int64(1), copied.GetInt64Array()[0], "the constructor copies vals")
+	copied.GetValues()[0] = NewIntValue(9)
+	assert.Equal(t, int64(1), copied.GetInt64Array()[0], "GetValues boxes a copy")
+	ints[0] = 1
+
+	obj := NewObject().SetValue("series", NewPackedInt64ArrayValue(ints...))
+	bs, err := proto.Marshal(obj)
+	assert.NoError(t, err)
+	decoded := &Object{}
+	assert.NoError(t, proto.Unmarshal(bs, decoded))
+	assert.True(t, decoded.GetValue("series").IsPackedArray())
+	assert.Equal(t, ints, decoded.GetValue("series").GetInt64Array())
+
+	series := make([]int64, 1000)
+	boxed := make([]*Value, 1000)
+	for i := range series {
+		series[i] = int64(i)
+		boxed[i] = NewInt64Value(int64(i))
+	}
+	assert.Less(t, proto.Size(NewInt64ArrayValue(series...)), proto.Size(NewArrayValue(boxed...))/2)
+
+	var paths []string
+	Walk(NewObjectValue(obj), func(path string, val *Value) WalkAction {
+		paths = append(paths, path)
+		return WalkContinue
+	})
+	assert.Equal(t, []string{"", "series", "series.0", "series.1", "series.2"}, paths)
+}
@@ -30,6 +30,9 @@ func (x *Values) AsSlice() []any {
 	return vs
 }
 
+// MarshalJSON encodes x in the protojson form, {"vals":[...]}, which
+// "encoding/json" users depend on; jsoniter encodes x as a plain array with
+// ValuesCodec instead.
 func (x *Values) MarshalJSON() ([]byte, error) {
 	return protojson.Marshal(x)
 }
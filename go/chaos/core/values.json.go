@@ -27,6 +27,5 @@ func (codec *ValuesCodec) IsEmpty(ptr unsafe.Pointer) bool {
 }
 
 func (codec *ValuesCodec) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
-	values := (*Values)(ptr)
-	stream.WriteVal(&values.Vals)
+	writeValues(stream, (*Values)(ptr).GetVals())
 }
@@ -0,0 +1,309 @@
+package core
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// The collection operations below never modify x, they return new Values
+// sharing the elements of x. Paths are dotted paths below each element, see
+// Value.GetPath, and the empty path is the element itself.
+
+// Filter returns the elements of x for which pred is true.
+func (x *Values) Filter(pred func(val *Value) bool) *Values {
+	vals := &Values{}
+	for _, v := range x.GetVals() {
+		if pred(v) {
+			vals.Vals = append(vals.Vals, v)
+		}
+	}
+	return vals
+}
+
+// Map returns the results of fn for every element of x, nil results are
+// stored as null values.
+func (x *Values) Map(fn func(val *Value) *Value) *Values {
+	vals := &Values{Vals: make([]*Value, 0, len(x.GetVals()))}
+	for _, v := range x.GetVals() {
+		mapped := fn(v)
+		if mapped == nil {
+			mapped = NewNullValue()
+		}
+		vals.Vals = append(vals.Vals, mapped)
+	}
+	return vals
+}
+
+// SortBy returns the elements of x stably sorted by the value at path. Missing
+// and null values sort first, then booleans, numbers, strings and others by
+// their JSON encoding.
+func (x *Values) SortBy(path string, desc bool) *Values {
+	vals := &Values{Vals: append([]*Value{}, x.GetVals()...)}
+	sort.SliceStable(vals.Vals, func(i, j int) bool {
+		c := CompareValues(vals.Vals[i].GetPath(path), vals.Vals[j].GetPath(path))
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+	return vals
+}
+
+// GroupBy groups the elements of x by the text of the value at path: strings
+// as they are, numbers and booleans as in JSON, objects and arrays in their
+// JSON encoding. Missing and null values are grouped under "".
+func (x *Values) GroupBy(path string) map[string]*Values {
+	groups := make(map[string]*Values)
+	for _, v := range x.GetVals() {
+		key := groupKey(v.GetPath(path))
+		group, ok := groups[key]
+		if !ok {
+			group = &Values{}
+			groups[key] = group
+		}
+		group.Vals = append(group.Vals, v)
+	}
+	return groups
+}
+
+// Distinct returns the distinct values at path in the order they are first
+// seen. Missing and null values are skipped, and numbers are distinct by
+// value, so 1 and 1.0 are the same.
+func (x *Values) Distinct(path string) *Values {
+	vals := &Values{}
+	seen := make(map[string]bool)
+	for _, v := range x.GetVals() {
+		val := v.GetPath(path)
+		if isNullValue(val) {
+			continue
+		}
+		key := distinctKey(val)
+		if !seen[key] {
+			seen[key] = true
+			vals.Vals = append(vals.Vals, val)
+		}
+	}
+	return vals
+}
+
+// Count returns the number of elements having a non-null value at path.
+func (x *Values) Count(path string) int {
+	count := 0
+	for _, v := range x.GetVals() {
+		if !isNullValue(v.GetPath(path)) {
+			count++
+		}
+	}
+	return count
+}
+
+// Sum returns the sum of the numbers at path, other values are ignored. The
+// sum is an integer if all the numbers are integers and it does not overflow
+// int64, and a number otherwise.
+func (x *Values) Sum(path string) *Value {
+	sum, isInt, _ := x.sum(path)
+	return sum.toValue(isInt)
+}
+
+// Avg returns the mean of the numbers at path as a number, or null if there
+// are none.
+func (x *Values) Avg(path string) *Value {
+	sum, _, count := x.sum(path)
+	if count == 0 {
+		return NewNullValue()
+	}
+	return NewFloat64Value(sum.f / float64(count))
+}
+
+// Min returns the smallest non-null value at path in the order of SortBy, or
+// null if there is none. The value is returned as it is, so integers stay
+// integers.
+func (x *Values) Min(path string) *Value {
+	return x.extreme(path, -1)
+}
+
+// Max returns the largest non-null value at path, see Min.
+func (x *Values) Max(path string) *Value {
+	return x.extreme(path, 1)
+}
+
+func (x *Values) extreme(path string, sign int) *Value {
+	var result *Value
+	for _, v := range x.GetVals() {
+		val := v.GetPath(path)
+		if isNullValue(val) {
+			continue
+		}
+		if result == nil || CompareValues(val, result)*sign > 0 {
+			result = val
+		}
+	}
+	if result == nil {
+		return NewNullValue()
+	}
+	return result
+}
+
+type numberSum struct {
+	i        int64
+	f        float64
+	overflow bool
+}
+
+func (s numberSum) toValue(isInt bool) *Value {
+	if isInt && !s.overflow {
+		return NewInt64Value(s.i)
+	}
+	return NewFloat64Value(s.f)
+}
+
+func (x *Values) sum(path string) (sum numberSum, isInt bool, count int) {
+	isInt = true
+	for _, v := range x.GetVals() {
+		val := v.GetPath(path)
+		switch val.GetKind() {
+		case ValueKind_VALUE_KIND_INTEGER:
+			f, _, _ := CoerceStrict.ToFloat64(val)
+			sum.f += f
+			if i, lossless, _ := CoerceStrict.ToInt64(val); !lossless || addOverflows(sum.i, i) {
+				sum.overflow = true
+			} else {
+				sum.i += i
+			}
+		case ValueKind_VALUE_KIND_NUMBER:
+			sum.f += val.GetFloat64()
+			isInt = false
+		default:
+			continue
+		}
+		count++
+	}
+	return sum, isInt, count
+}
+
+func addOverflows(a, b int64) bool {
+	return b > 0 && a > math.MaxInt64-b || b < 0 && a < math.MinInt64-b
+}
+
+// CompareValues orders two values the way SortBy does, returning -1, 0 or 1.
+func CompareValues(a, b *Value) int {
+	ra, rb := compareRank(a), compareRank(b)
+	if ra != rb {
+		if ra < rb {
+			return -1
+		}
+		return 1
+	}
+
+	switch ra {
+	case 1:
+		return compareBool(a.GetBool(), b.GetBool())
+	case 2:
+		return compareNumbers(a, b)
+	case 3:
+		return strings.Compare(a.GetString(), b.GetString())
+	case 4:
+		sa, _ := jsoniter.ConfigFastest.MarshalToString(a)
+		sb, _ := jsoniter.ConfigFastest.MarshalToString(b)
+		return strings.Compare(sa, sb)
+	}
+	return 0
+}
+
+func compareRank(v *Value) int {
+	switch v.GetKind() {
+	case ValueKind_VALUE_KIND_BOOLEAN:
+		return 1
+	case ValueKind_VALUE_KIND_INTEGER, ValueKind_VALUE_KIND_NUMBER:
+		return 2
+	case ValueKind_VALUE_KIND_STRING:
+		return 3
+	case ValueKind_VALUE_KIND_NULL, ValueKind_VALUE_KIND_UNSPECIFIED:
+		return 0
+	default:
+		return 4
+	}
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	default:
+		return 1
+	}
+}
+
+func compareNumbers(a, b *Value) int {
+	if a.GetKind() == ValueKind_VALUE_KIND_INTEGER && b.GetKind() == ValueKind_VALUE_KIND_INTEGER {
+		// compare exactly, beyond the 53 bits of float64
+		an, bn := a.GetNegativeValue() > 0, b.GetNegativeValue() > 0
+		switch {
+		case an && !bn:
+			return -1
+		case !an && bn:
+			return 1
+		case an:
+			return compareUint(b.GetNegativeValue(), a.GetNegativeValue())
+		default:
+			return compareUint(a.GetPositiveValue(), b.GetPositiveValue())
+		}
+	}
+
+	fa, _, _ := CoerceStrict.ToFloat64(a)
+	fb, _, _ := CoerceStrict.ToFloat64(b)
+	switch {
+	case fa < fb:
+		return -1
+	case fa > fb:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func isNullValue(v *Value) bool {
+	kind := v.GetKind()
+	return kind == ValueKind_VALUE_KIND_NULL || kind == ValueKind_VALUE_KIND_UNSPECIFIED
+}
+
+func groupKey(v *Value) string {
+	if isNullValue(v) {
+		return ""
+	}
+	if s, _, err := CoerceJSONLenient.ToString(v); err == nil {
+		return s
+	}
+	s, _ := jsoniter.ConfigFastest.MarshalToString(v)
+	return s
+}
+
+func distinctKey(v *Value) string {
+	switch v.GetKind() {
+	case ValueKind_VALUE_KIND_INTEGER, ValueKind_VALUE_KIND_NUMBER:
+		if i, lossless, err := CoerceJSONLenient.ToInt64(v); err == nil && lossless {
+			return "n" + groupKey(NewInt64Value(i))
+		}
+		return "n" + groupKey(v)
+	case ValueKind_VALUE_KIND_STRING:
+		return "s" + v.GetString()
+	}
+	s, _ := jsoniter.ConfigFastest.MarshalToString(v)
+	return "j" + s
+}
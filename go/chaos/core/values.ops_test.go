@@ -0,0 +1,122 @@
+package core
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func names(vals *Values) []string {
+	var ns []string
+	for _, v := range vals.GetVals() {
+		ns = append(ns, v.GetObject().GetString("name"))
+	}
+	return ns
+}
+
+func TestValues_FilterMap(t *testing.T) {
+	vals := NewObjectArrayValue(
+		NewObject().SetString("name", "a").SetString("team", "red").SetInt("score", 3).SetObject("meta", NewObject().SetFloat64("weight", 1.5)),
+		NewObject().SetString("name", "b").SetString("team", "blue").SetInt("score", -1),
+		NewObject().SetString("name", "c").SetString("team", "red").SetInt("score", 10).SetObject("meta", NewObject().SetFloat64("weight", 0.5)),
+		NewObject().SetString("name", "d").SetValue("team", NewNullValue()),
+	).GetValuesValue()
+
+	red := vals.Filter(func(val *Value) bool {
+		return val.GetPath("team").GetString() == "red"
+	})
+	assert.Equal(t, []string{"a", "c"}, names(red))
+
+	scores := red.Map(func(val *Value) *Value {
+		return val.GetPath("score")
+	})
+	assert.Equal(t, []int64{3, 10}, NewValuesValue(scores).GetInt64Array())
+
+	nulls := vals.Map(func(val *Value) *Value { return nil })
+	assert.Equal(t, 4, len(nulls.Vals))
+	assert.Equal(t, ValueKind_VALUE_KIND_NULL, nulls.Vals[0].GetKind())
+	assert.Len(t, vals.Vals, 4)
+}
+
+func TestValues_SortBy(t *testing.T) {
+	vals := NewObjectArrayValue(
+		NewObject().SetString("name", "a").SetString("team", "red").SetInt("score", 3).SetObject("meta", NewObject().SetFloat64("weight", 1.5)),
+		NewObject().SetString("name", "b").SetString("team", "blue").SetInt("score", -1),
+		NewObject().SetString("name", "c").SetString("team", "red").SetInt("score", 10).SetObject("meta", NewObject().SetFloat64("weight", 0.5)),
+		NewObject().SetString("name", "d").SetValue("team", NewNullValue()),
+	).GetValuesValue()
+
+	assert.Equal(t, []string{"d", "b", "a", "c"}, names(vals.SortBy("score", false)))
+	assert.Equal(t, []string{"c", "a", "b", "d"}, names(vals.SortBy("score", true)))
+	assert.Equal(t, []string{"d", "b", "a", "c"}, names(vals.SortBy("team", false)))
+	assert.Equal(t, []string{"b", "d", "c", "a"}, names(vals.SortBy("meta.weight", false)))
+	assert.Equal(t, []string{"a", "b", "c", "d"}, names(vals), "the source is left untouched")
+
+	mixed := NewArrayValue(NewFloat64Value(2.5), NewUint64Value(math.MaxUint64), NewIntValue(2), NewStringValue("x"), NewBoolValue(true)).GetValuesValue()
+	assert.Equal(t, []any{true, uint64(2), 2.5, uint64(math.MaxUint64), "x"}, mixed.SortBy("", false).AsSlice())
+}
+
+func TestValues_GroupBy(t *testing.T) {
+	vals := NewObjectArrayValue(
+		NewObject().SetString("name", "a").SetString("team", "red").SetInt("score", 3).SetObject("meta", NewObject().SetFloat64("weight", 1.5)),
+		NewObject().SetString("name", "b").SetString("team", "blue").SetInt("score", -1),
+		NewObject().SetString("name", "c").SetString("team", "red").SetInt("score", 10).SetObject("meta", NewObject().SetFloat64("weight", 0.5)),
+		NewObject().SetString("name", "d").SetValue("team", NewNullValue()),
+	).GetValuesValue()
+
+	groups := vals.GroupBy("team")
+	assert.Len(t, groups, 3)
+	assert.Equal(t, []string{"a", "c"}, names(groups["red"]))
+	assert.Equal(t, []string{"b"}, names(groups["blue"]))
+	assert.Equal(t, []string{"d"}, names(groups[""]))
+
+	groups = vals.GroupBy("score")
+	assert.Equal(t, []string{"b"}, names(groups["-1"]))
+}
+
+func TestValues_Distinct(t *testing.T) {
+	teams := NewObjectArrayValue(
+		NewObject().SetString("team", "red"),
+		NewObject().SetString("team", "blue"),
+		NewObject().SetString("team", "red"),
+		NewObject().SetValue("team", NewNullValue()),
+	).GetValuesValue()
+	assert.Equal(t, []any{"red", "blue"}, teams.Distinct("team").AsSlice())
+
+	vals := NewArrayValue(NewIntValue(1), NewFloat64Value(1), NewStringValue("1"), NewNullValue(), NewIntValue(2)).GetValuesValue()
+	assert.Equal(t, []any{uint64(1), "1", uint64(2)}, vals.Distinct("").AsSlice())
+}
+
+func TestValues_Aggregates(t *testing.T) {
+	vals := NewObjectArrayValue(
+		NewObject().SetString("name", "a").SetString("team", "red").SetInt("score", 3).SetObject("meta", NewObject().SetFloat64("weight", 1.5)),
+		NewObject().SetString("name", "b").SetString("team", "blue").SetInt("score", -1),
+		NewObject().SetString("name", "c").SetString("team", "red").SetInt("score", 10).SetObject("meta", NewObject().SetFloat64("weight", 0.5)),
+		NewObject().SetString("name", "d").SetValue("team", NewNullValue()),
+	).GetValuesValue()
+
+	assert.Equal(t, 3, vals.Count("team"))
+	assert.Equal(t, 4, vals.Count(""))
+
+	sum := vals.Sum("score")
+	assert.Equal(t, ValueKind_VALUE_KIND_INTEGER, sum.GetKind())
+	assert.Equal(t, int64(12), sum.GetInt64())
+
+	sum = vals.Sum("meta.weight")
+	assert.Equal(t, ValueKind_VALUE_KIND_NUMBER, sum.GetKind())
+	assert.Equal(t, 2.0, sum.GetFloat64())
+
+	assert.Equal(t, 4.0, vals.Avg("score").GetFloat64())
+	assert.Equal(t, ValueKind_VALUE_KIND_NULL, vals.Avg("missing").GetKind())
+
+	assert.Equal(t, ValueKind_VALUE_KIND_INTEGER, vals.Min("score").GetKind())
+	assert.Equal(t, int64(-1), vals.Min("score").GetInt64())
+	assert.Equal(t, int64(10), vals.Max("score").GetInt64())
+	assert.Equal(t, "red", vals.Max("team").GetString())
+	assert.Equal(t, ValueKind_VALUE_KIND_NULL, vals.Max("missing").GetKind())
+
+	overflow := NewArrayValue(NewInt64Value(math.MaxInt64), NewIntValue(1)).GetValuesValue()
+	assert.Equal(t, ValueKind_VALUE_KIND_NUMBER, overflow.Sum("").GetKind())
+	assert.Equal(t, int64(0), (&Values{}).Sum("").GetInt64())
+}
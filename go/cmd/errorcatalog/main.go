@@ -0,0 +1,101 @@
+// Command errorcatalog writes the catalog of the registered error codes as
+// markdown, HTML or JSON.
+//
+// It lists the codes registered by the packages linked into it, the built-in
+// ones of chaos/core here. Services having their own codes build their copy
+// of it importing the packages registering them, or call
+// core.WriteErrorCatalog.
+//
+// Usage:
+//
+//	errorcatalog [-format markdown|html|json] [-o file] [-base-url url] [-domain domain,...]
+//	errorcatalog -dir docs [-base-url url] [-domain domain,...]
+//
+// With -dir, errors.md, errors.html and errors.json are written into the
+// directory. With -base-url, the codes having no document get the Url of
+// their entry in the catalog published there.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chaos-io/core/go/chaos/core"
+)
+
+var formatFiles = map[string]string{
+	core.ErrorCatalogMarkdown: "errors.md",
+	core.ErrorCatalogHTML:     "errors.html",
+	core.ErrorCatalogJSON:     "errors.json",
+}
+
+func main() {
+	format := flag.String("format", "", "the format of the catalog: markdown, html or json, by the extension of -o by default")
+	output := flag.String("o", "", "the file to write, the standard output by default")
+	dir := flag.String("dir", "", "the directory to write the catalogs in all the formats into")
+	baseURL := flag.String("base-url", "", "the url the catalog is published at, to set the documents of the codes")
+	domains := flag.String("domain", "", "the comma separated domains to list, all by default")
+	flag.Parse()
+
+	if err := run(*format, *output, *dir, *baseURL, *domains); err != nil {
+		fmt.Fprintln(os.Stderr, "errorcatalog:", err)
+		os.Exit(1)
+	}
+}
+
+func run(format, output, dir, baseURL, domains string) error {
+	var domainList []string
+	if len(domains) > 0 {
+		domainList = strings.Split(domains, ",")
+	}
+	if len(baseURL) > 0 {
+		if err := core.SetErrorCodeDocuments(baseURL, domainList...); err != nil {
+			return err
+		}
+	}
+	codes := core.ErrorCodes(domainList...)
+
+	if len(dir) > 0 {
+		for _, f := range []string{core.ErrorCatalogMarkdown, core.ErrorCatalogHTML, core.ErrorCatalogJSON} {
+			if err := writeFile(filepath.Join(dir, formatFiles[f]), f, codes); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(format) == 0 {
+		format = formatOf(output)
+	}
+	if len(output) == 0 {
+		return core.WriteErrorCatalog(os.Stdout, format, codes)
+	}
+	return writeFile(output, format, codes)
+}
+
+// formatOf returns the format of a file by its extension, markdown by default.
+func formatOf(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".html", ".htm":
+		return core.ErrorCatalogHTML
+	case ".json":
+		return core.ErrorCatalogJSON
+	}
+	return core.ErrorCatalogMarkdown
+}
+
+func writeFile(file, format string, codes []*core.ErrorCode) (err error) {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := f.Close(); err == nil {
+			err = cErr
+		}
+	}()
+	return core.WriteErrorCatalog(f, format, codes)
+}
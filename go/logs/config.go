@@ -1,5 +1,15 @@
 package logs
 
+import (
+	"fmt"
+
+	"github.com/chaos-io/core/go/chaos/core"
+)
+
+// EnvPrefix is the prefix of the environment variables read by LoadConfig,
+// e.g. LOGS_LEVEL or LOGS_FILE_MAX_SIZE.
+const EnvPrefix = "LOGS"
+
 type Config struct {
 	InitFields   map[string]interface{} `json:"initFields"`
 	Level        string                 `json:"level" default:"debug"`    // debug,info,warn,error,fatal
@@ -7,6 +17,7 @@ type Config struct {
 	LevelPattern string                 `json:"levelPattern" default:""`
 	LevelPort    int                    `json:"levelPort" default:"0"`
 	Output       string                 `json:"output" default:"console"` // console,file
+	Redact       bool                   `json:"redact" default:"false"`   // mask sensitive fields, see core.DefaultRedactor
 	File         FileConfig             `json:"file"`
 }
 
@@ -19,17 +30,27 @@ type FileConfig struct {
 	Compress   bool   `json:"compress"`
 }
 
+// NewDefaultConfig returns the config of the `default` struct tags. It panics
+// if the tags can not be loaded, which is a bug of Config.
 func NewDefaultConfig() *Config {
-	return &Config{
-		Level:  "debug",
-		Encode: "console",
-		Output: "console",
-		File: FileConfig{
-			Path:       "./logs/app.log",
-			Encode:     "json",
-			MaxSize:    100,
-			MaxBackups: 10,
-			MaxAge:     30,
-		},
+	cfg := &Config{}
+	if err := core.NewConfigLoader().Load(cfg); err != nil {
+		panic(fmt.Errorf("invalid default log config: %w", err))
+	}
+	return cfg
+}
+
+// LoadConfig reads the `logs` section of the given JSON/YAML files on top of
+// the defaults, then applies the LOGS_* environment variables.
+func LoadConfig(files ...string) (*Config, error) {
+	cfg := &Config{}
+	err := core.NewConfigLoader().
+		WithFile(files...).
+		WithSection("logs").
+		WithEnvPrefix(EnvPrefix).
+		Load(cfg)
+	if err != nil {
+		return nil, err
 	}
+	return cfg, nil
 }
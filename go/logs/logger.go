@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/chaos-io/core/go/chaos/core"
 	jsoniter "github.com/json-iterator/go"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -37,9 +38,10 @@ type Entry struct {
 }
 
 type zapLogger struct {
-	level  zap.AtomicLevel
-	sugar  *zap.SugaredLogger
-	fields []Field
+	level    zap.AtomicLevel
+	sugar    *zap.SugaredLogger
+	fields   []Field
+	redactor *core.Redactor
 }
 
 func NewLoggerWith(cfg *Config) Logger {
@@ -49,11 +51,28 @@ func NewLoggerWith(cfg *Config) Logger {
 	return newZapLogger(cfg, nil)
 }
 
+// NewLoggerWithRedactor creates a logger which masks sensitive fields with
+// the given redactor, see core.Redactor.RedactField.
+func NewLoggerWithRedactor(cfg *Config, redactor *core.Redactor) Logger {
+	if cfg == nil {
+		cfg = NewDefaultConfig()
+	}
+	return newRedactedZapLogger(cfg, nil, redactor)
+}
+
 func newZapLogger(cfg *Config, sink io.Writer) *zapLogger {
 	if cfg == nil {
 		cfg = NewDefaultConfig()
 	}
 
+	var redactor *core.Redactor
+	if cfg.Redact {
+		redactor = core.DefaultRedactor()
+	}
+	return newRedactedZapLogger(cfg, sink, redactor)
+}
+
+func newRedactedZapLogger(cfg *Config, sink io.Writer, redactor *core.Redactor) *zapLogger {
 	level := zap.NewAtomicLevel()
 	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
 		level.SetLevel(zapcore.InfoLevel)
@@ -72,7 +91,7 @@ func newZapLogger(cfg *Config, sink io.Writer) *zapLogger {
 	if len(cfg.InitFields) > 0 {
 		fields := make([]zap.Field, 0, len(cfg.InitFields))
 		for key, value := range cfg.InitFields {
-			fields = append(fields, zap.Any(key, value))
+			fields = append(fields, zap.Any(key, redactor.RedactField(key, value)))
 		}
 		base = base.With(fields...)
 	}
@@ -84,8 +103,9 @@ func newZapLogger(cfg *Config, sink io.Writer) *zapLogger {
 	}
 
 	return &zapLogger{
-		level: level,
-		sugar: base.Sugar(),
+		level:    level,
+		sugar:    base.Sugar(),
+		redactor: redactor,
 	}
 }
 
@@ -181,9 +201,10 @@ func (l *zapLogger) clone() *zapLogger {
 	fields := make([]Field, len(l.fields))
 	copy(fields, l.fields)
 	return &zapLogger{
-		level:  l.level,
-		sugar:  l.sugar,
-		fields: fields,
+		level:    l.level,
+		sugar:    l.sugar,
+		fields:   fields,
+		redactor: l.redactor,
 	}
 }
 
@@ -200,15 +221,17 @@ func (l *zapLogger) With(fields ...Field) Logger {
 		return l.clone()
 	}
 
+	fields = l.redact(fields)
 	kv := make([]any, 0, len(fields)*2)
 	for _, field := range fields {
 		kv = append(kv, field.Key, field.Value)
 	}
 
 	return &zapLogger{
-		level:  l.level,
-		sugar:  l.sugar.With(kv...),
-		fields: append(append([]Field{}, l.fields...), fields...),
+		level:    l.level,
+		sugar:    l.sugar.With(kv...),
+		fields:   append(append([]Field{}, l.fields...), fields...),
+		redactor: l.redactor,
 	}
 }
 
@@ -223,7 +246,7 @@ func (l *zapLogger) Log(entry Entry) {
 	}
 
 	sugar := l.sugar.WithOptions(zap.AddCallerSkip(callerSkip))
-	kv := fieldsToKeyValues(l.fields, entry.Fields)
+	kv := fieldsToKeyValues(l.fields, l.redact(entry.Fields))
 	switch entry.Level {
 	case DebugLevel:
 		if len(kv) == 0 {
@@ -276,6 +299,18 @@ func (l *zapLogger) Log(entry Entry) {
 	}
 }
 
+func (l *zapLogger) redact(fields []Field) []Field {
+	if l.redactor == nil || len(fields) == 0 {
+		return fields
+	}
+
+	redacted := make([]Field, len(fields))
+	for i, field := range fields {
+		redacted[i] = Field{Key: field.Key, Value: l.redactor.RedactField(field.Key, field.Value)}
+	}
+	return redacted
+}
+
 func fieldsToKeyValues(base []Field, extra []Field) []any {
 	total := len(base) + len(extra)
 	if total == 0 {
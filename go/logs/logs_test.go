@@ -2,6 +2,8 @@ package logs
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net"
 	"net/http"
@@ -12,6 +14,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/chaos-io/core/go/chaos/core"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -176,3 +179,57 @@ func TestFileOutput(t *testing.T) {
 	require.True(t, strings.Contains(string(data), `"message":"persisted"`))
 	require.True(t, strings.Contains(string(data), `"user":"bob"`))
 }
+
+func TestNewDefaultConfig(t *testing.T) {
+	cfg := NewDefaultConfig()
+	require.Equal(t, "debug", cfg.Level)
+	require.Equal(t, "console", cfg.Encode)
+	require.Equal(t, "console", cfg.Output)
+	require.Equal(t, "./logs/app.log", cfg.File.Path)
+	require.Equal(t, "json", cfg.File.Encode)
+	require.Equal(t, 100, cfg.File.MaxSize)
+	require.Equal(t, 10, cfg.File.MaxBackups)
+	require.Equal(t, 30, cfg.File.MaxAge)
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("LOGS_LEVEL", "warn")
+
+	cfg, err := LoadConfig(filepath.Join("configs", "logs.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, "warn", cfg.Level)
+	require.Equal(t, "./log/app.log", cfg.File.Path)
+	require.Equal(t, 100, cfg.File.MaxSize)
+	require.Equal(t, "console", cfg.Output)
+}
+
+func TestLoggerRedact(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := newZapLogger(&Config{
+		Level:  "info",
+		Encode: "json",
+		Output: "console",
+		Redact: true,
+	}, buf)
+
+	logger.With(Field{Key: "authorization", Value: "Bearer abc"}).Log(Entry{
+		Level:   InfoLevel,
+		Message: "request",
+		Fields: []Field{
+			{Key: "password", Value: "p@ss"},
+			{Key: "body", Value: core.NewObject().SetString("token", "tok-4f2a").SetString("name", "bob")},
+		},
+	})
+
+	out := buf.String()
+	require.NotContains(t, out, "p@ss")
+	require.NotContains(t, out, "Bearer abc")
+	require.Contains(t, out, `"password":"******"`)
+
+	entry := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	body := fmt.Sprint(entry["body"])
+	require.NotContains(t, body, "tok-4f2a")
+	require.Contains(t, body, core.RedactedMask)
+	require.Contains(t, body, "bob")
+}